@@ -1,13 +1,15 @@
 package attributes
 
 import (
-	"errors"
 	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
 )
 
 type Bool struct {
 	AttributeStub
 	Key        string
+	Access     string
 	ColumnName string
 	Nullable   bool
 	Default    func(interface{}) BoolValue
@@ -16,6 +18,9 @@ type Bool struct {
 func (b Bool) GetKey() string {
 	return b.Key
 }
+func (b Bool) GetAccess() string {
+	return b.Access
+}
 
 func (b Bool) GetSelectDirect() ([]string, []interface{}) {
 	var destination *bool
@@ -24,6 +29,11 @@ func (b Bool) GetSelectDirect() ([]string, []interface{}) {
 	}
 }
 
+func (b Bool) WithColumn(column string) schema.Attribute {
+	b.ColumnName = column
+	return &b
+}
+
 func (b Bool) GetOrderMap() map[string]string {
 	orders := map[string]string{}
 	orders[b.Key] = b.ColumnName
@@ -31,20 +41,23 @@ func (b Bool) GetOrderMap() map[string]string {
 }
 
 func (b *Bool) DefaultFallback(val interface{}, instance interface{}) (interface{}, error) {
-	boolVal := AssertBool(val)
-	if !boolVal.Provided {
+	if val == nil || !AssertBool(val).Provided {
 		if b.Default != nil {
 			return b.Default(instance), nil
 		}
 		return nil, nil
 	}
-	return boolVal, nil
+	return AssertBool(val), nil
 }
 func (b *Bool) Validate(val interface{}) (interface{}, error) {
 	boolVal := AssertBool(val)
 	if boolVal.Value == nil {
 		if !b.Nullable {
-			return nil, errors.New(fmt.Sprintf("Attribute '%s' cannot be null.", b.Key))
+			return nil, utils.AttributeError(
+				b.Key,
+				utils.CODE_NULL,
+				fmt.Sprintf("Attribute '%s' cannot be null.", b.Key),
+			)
 		}
 	}
 	return boolVal, nil
@@ -83,6 +83,7 @@ func (b Bool) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 	if exists {
 		if len(nullStrings) != 1 {
 			return filters.Exception(
+				nullKey,
 				"Cannot null check attribute '%s' against more than one value.",
 				b.Key,
 			)
@@ -108,6 +109,7 @@ func (b Bool) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 			})
 		} else {
 			return filters.Exception(
+				nullKey,
 				"Invalid null check value on attribute '%s'. Must be boolean.",
 				b.Key,
 			)
@@ -119,6 +121,7 @@ func (b Bool) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 	if exists {
 		if len(exactStrings) != 1 {
 			return filters.Exception(
+				exactKey,
 				"Cannot compare attribute '%s' against more than one value.",
 				b.Key,
 			)
@@ -145,6 +148,7 @@ func (b Bool) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 			})
 		} else {
 			return filters.Exception(
+				exactKey,
 				"Invalid comparison value on attribute '%s'. Must be boolean.",
 				b.Key,
 			)
@@ -0,0 +1,67 @@
+package attributes
+
+import (
+	"github.com/bor3ham/reja/schema"
+)
+
+// attributes that can be read from any sql expression producing their type
+type Expressible interface {
+	schema.Attribute
+	WithColumn(string) schema.Attribute
+}
+
+// a read only value calculated by the database, such as a concatenation or a
+// subquery count
+// schema.TABLE_PLACEHOLDER within the expression is replaced by the model table
+type Computed struct {
+	AttributeStub
+	Expression string
+	// the type of value produced, which provides the key, ordering and filters
+	// its column name is ignored
+	Attribute Expressible
+}
+
+func (c Computed) produced() schema.Attribute {
+	return c.Attribute.WithColumn("(" + c.Expression + ")")
+}
+
+func (c Computed) GetKey() string {
+	return c.Attribute.GetKey()
+}
+func (c Computed) GetAccess() string {
+	return schema.ACCESS_READ_ONLY
+}
+
+func (c Computed) GetSelectDirect() ([]string, []interface{}) {
+	return c.produced().GetSelectDirect()
+}
+
+func (c Computed) GetOrderMap() map[string]string {
+	return c.produced().GetOrderMap()
+}
+
+func (c Computed) AvailableFilters() []interface{} {
+	return c.produced().AvailableFilters()
+}
+func (c Computed) ValidateFilters(queries map[string][]string) ([]schema.Filter, error) {
+	produced, err := c.produced().ValidateFilters(queries)
+	if err != nil {
+		return produced, err
+	}
+	valids := []schema.Filter{}
+	for _, filter := range produced {
+		valids = append(valids, ComputedFilter{
+			Filter: filter,
+		})
+	}
+	return valids, nil
+}
+
+// computed values are never written
+func (c *Computed) DefaultFallback(val interface{}, instance interface{}) (interface{}, error) {
+	return nil, nil
+}
+func (c *Computed) ValidateUpdate(newVal interface{}, oldVal interface{}) (interface{}, error) {
+	// changed values are returned so they are rejected as read only
+	return c.Attribute.ValidateUpdate(newVal, oldVal)
+}
@@ -0,0 +1,27 @@
+package attributes
+
+import (
+	"github.com/bor3ham/reja/schema"
+)
+
+// places the model table into the expression of a produced filter
+type ComputedFilter struct {
+	schema.Filter
+}
+
+func (f ComputedFilter) GetWhere(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) (
+	[]string,
+	[]interface{},
+) {
+	queries, args := f.Filter.GetWhere(c, modelTable, idColumn, nextArg)
+	tabled := []string{}
+	for _, query := range queries {
+		tabled = append(tabled, schema.TableExpression(query, modelTable))
+	}
+	return tabled, args
+}
@@ -1,8 +1,9 @@
 package attributes
 
 import (
-	"errors"
 	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
 	"time"
 )
 
@@ -11,6 +12,7 @@ const DATE_LAYOUT = "2006-01-02"
 type Date struct {
 	AttributeStub
 	Key        string
+	Access     string
 	ColumnName string
 	Nullable   bool
 	Default    func(interface{}) DateValue
@@ -19,6 +21,9 @@ type Date struct {
 func (d Date) GetKey() string {
 	return d.Key
 }
+func (d Date) GetAccess() string {
+	return d.Access
+}
 
 func (d Date) GetSelectDirect() ([]string, []interface{}) {
 	var destination *time.Time
@@ -27,6 +32,11 @@ func (d Date) GetSelectDirect() ([]string, []interface{}) {
 	}
 }
 
+func (d Date) WithColumn(column string) schema.Attribute {
+	d.ColumnName = column
+	return &d
+}
+
 func (d Date) GetOrderMap() map[string]string {
 	orders := map[string]string{}
 	orders[d.Key] = d.ColumnName
@@ -46,7 +56,11 @@ func (d *Date) Validate(val interface{}) (interface{}, error) {
 	dVal := AssertDate(val)
 	if dVal.Value == nil {
 		if !d.Nullable {
-			return nil, errors.New(fmt.Sprintf("Attribute '%s' cannot be null.", d.Key))
+			return nil, utils.AttributeError(
+				d.Key,
+				utils.CODE_NULL,
+				fmt.Sprintf("Attribute '%s' cannot be null.", d.Key),
+			)
 		}
 	}
 	return dVal, nil
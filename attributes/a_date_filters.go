@@ -133,6 +133,7 @@ func (d Date) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 	if exists {
 		if len(nullStrings) != 1 {
 			return filters.Exception(
+				nullKey,
 				"Cannot null check attribute '%s' against more than one value.",
 				d.Key,
 			)
@@ -158,6 +159,7 @@ func (d Date) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 			})
 		} else {
 			return filters.Exception(
+				nullKey,
 				"Invalid null check value on attribute '%s'. Must be boolean.",
 				d.Key,
 			)
@@ -169,6 +171,7 @@ func (d Date) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 	if exists {
 		if len(exactStrings) != 1 {
 			return filters.Exception(
+				exactKey,
 				"Cannot compare attribute '%s' against more than one value.",
 				d.Key,
 			)
@@ -186,6 +189,7 @@ func (d Date) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 			})
 		} else {
 			return filters.Exception(
+				exactKey,
 				"Invalid exact value on attribute '%s'. Must be date in format %s.",
 				d.Key,
 				DATE_LAYOUT,
@@ -198,6 +202,7 @@ func (d Date) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 	if exists {
 		if len(afterStrings) != 1 {
 			return filters.Exception(
+				afterKey,
 				"Cannot compare attribute '%s' to be after more than one value.",
 				d.Key,
 			)
@@ -216,6 +221,7 @@ func (d Date) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 			})
 		} else {
 			return filters.Exception(
+				afterKey,
 				"Invalid after comparison value on attribute '%s'. Must be date in format %s.",
 				d.Key,
 				DATE_LAYOUT,
@@ -228,6 +234,7 @@ func (d Date) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 	if exists {
 		if len(beforeStrings) != 1 {
 			return filters.Exception(
+				beforeKey,
 				"Cannot compare attribute '%s' to be before more than one value.",
 				d.Key,
 			)
@@ -236,6 +243,7 @@ func (d Date) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 		beforeValue, err := time.Parse(DATE_LAYOUT, beforeStrings[0])
 		if err != nil {
 			return filters.Exception(
+				beforeKey,
 				"Invalid before comparison value on attribute '%s'. Must be date in format %s.",
 				d.Key,
 				DATE_LAYOUT,
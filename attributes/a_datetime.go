@@ -1,14 +1,16 @@
 package attributes
 
 import (
-	"errors"
 	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
 	"time"
 )
 
 type Datetime struct {
 	AttributeStub
 	Key        string
+	Access     string
 	ColumnName string
 	Nullable   bool
 	Default    func(interface{}) DatetimeValue
@@ -17,6 +19,9 @@ type Datetime struct {
 func (dt Datetime) GetKey() string {
 	return dt.Key
 }
+func (dt Datetime) GetAccess() string {
+	return dt.Access
+}
 
 func (dt Datetime) GetSelectDirect() ([]string, []interface{}) {
 	var destination *time.Time
@@ -25,6 +30,11 @@ func (dt Datetime) GetSelectDirect() ([]string, []interface{}) {
 	}
 }
 
+func (dt Datetime) WithColumn(column string) schema.Attribute {
+	dt.ColumnName = column
+	return &dt
+}
+
 func (dt Datetime) GetOrderMap() map[string]string {
 	orders := map[string]string{}
 	orders[dt.Key] = dt.ColumnName
@@ -44,7 +54,11 @@ func (dt *Datetime) Validate(val interface{}) (interface{}, error) {
 	dtVal := AssertDatetime(val)
 	if dtVal.Value == nil {
 		if !dt.Nullable {
-			return nil, errors.New(fmt.Sprintf("Attribute '%s' cannot be null.", dt.Key))
+			return nil, utils.AttributeError(
+				dt.Key,
+				utils.CODE_NULL,
+				fmt.Sprintf("Attribute '%s' cannot be null.", dt.Key),
+			)
 		}
 	}
 	return dtVal, nil
@@ -137,6 +137,7 @@ func (dt Datetime) ValidateFilters(queries map[string][]string) ([]schema.Filter
 	if exists {
 		if len(nullStrings) != 1 {
 			return filters.Exception(
+				nullKey,
 				"Cannot null check attribute '%s' against more than one value.",
 				dt.Key,
 			)
@@ -162,6 +163,7 @@ func (dt Datetime) ValidateFilters(queries map[string][]string) ([]schema.Filter
 			})
 		} else {
 			return filters.Exception(
+				nullKey,
 				"Invalid null check value on attribute '%s'. Must be boolean.",
 				dt.Key,
 			)
@@ -173,6 +175,7 @@ func (dt Datetime) ValidateFilters(queries map[string][]string) ([]schema.Filter
 	if exists {
 		if len(exactStrings) != 1 {
 			return filters.Exception(
+				exactKey,
 				"Cannot compare attribute '%s' against more than one value.",
 				dt.Key,
 			)
@@ -190,6 +193,7 @@ func (dt Datetime) ValidateFilters(queries map[string][]string) ([]schema.Filter
 			})
 		} else {
 			return filters.Exception(
+				exactKey,
 				"Invalid exact value on attribute '%s'. Must be datetime in RFC3339 format.",
 				dt.Key,
 			)
@@ -201,6 +205,7 @@ func (dt Datetime) ValidateFilters(queries map[string][]string) ([]schema.Filter
 	if exists {
 		if len(afterStrings) != 1 {
 			return filters.Exception(
+				afterKey,
 				"Cannot compare attribute '%s' to be after more than one value.",
 				dt.Key,
 			)
@@ -219,6 +224,7 @@ func (dt Datetime) ValidateFilters(queries map[string][]string) ([]schema.Filter
 			})
 		} else {
 			return filters.Exception(
+				afterKey,
 				"Invalid after comparison value on attribute '%s'. Must be datetime in RFC3339 format.",
 				dt.Key,
 			)
@@ -230,6 +236,7 @@ func (dt Datetime) ValidateFilters(queries map[string][]string) ([]schema.Filter
 	if exists {
 		if len(beforeStrings) != 1 {
 			return filters.Exception(
+				beforeKey,
 				"Cannot compare attribute '%s' to be before more than one value.",
 				dt.Key,
 			)
@@ -248,6 +255,7 @@ func (dt Datetime) ValidateFilters(queries map[string][]string) ([]schema.Filter
 			})
 		} else {
 			return filters.Exception(
+				beforeKey,
 				"Invalid before comparison value on attribute '%s'. Must be datetime in RFC3339 format.",
 				dt.Key,
 			)
@@ -1,14 +1,16 @@
 package attributes
 
 import (
-	"errors"
 	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
 	"github.com/shopspring/decimal"
 )
 
 type Decimal struct {
 	AttributeStub
 	Key           string
+	Access        string
 	ColumnName    string
 	DecimalPlaces int32
 	Nullable      bool
@@ -18,6 +20,9 @@ type Decimal struct {
 func (d Decimal) GetKey() string {
 	return d.Key
 }
+func (d Decimal) GetAccess() string {
+	return d.Access
+}
 
 func (d Decimal) GetSelectDirect() ([]string, []interface{}) {
 	var destination *decimal.Decimal
@@ -26,6 +31,11 @@ func (d Decimal) GetSelectDirect() ([]string, []interface{}) {
 	}
 }
 
+func (d Decimal) WithColumn(column string) schema.Attribute {
+	d.ColumnName = column
+	return &d
+}
+
 func (d Decimal) GetOrderMap() map[string]string {
 	orders := map[string]string{}
 	orders[d.Key] = d.ColumnName
@@ -33,20 +43,23 @@ func (d Decimal) GetOrderMap() map[string]string {
 }
 
 func (d *Decimal) DefaultFallback(val interface{}, instance interface{}) (interface{}, error) {
-	dVal := AssertDecimal(val, d.DecimalPlaces)
-	if !dVal.Provided {
+	if val == nil || !AssertDecimal(val, d.DecimalPlaces).Provided {
 		if d.Default != nil {
 			return d.Default(instance), nil
 		}
 		return nil, nil
 	}
-	return dVal, nil
+	return AssertDecimal(val, d.DecimalPlaces), nil
 }
 func (d *Decimal) Validate(val interface{}) (interface{}, error) {
 	dVal := AssertDecimal(val, d.DecimalPlaces)
 	if dVal.Value == nil {
 		if !d.Nullable {
-			return nil, errors.New(fmt.Sprintf("Attribute '%s' cannot be null.", d.Key))
+			return nil, utils.AttributeError(
+				d.Key,
+				utils.CODE_NULL,
+				fmt.Sprintf("Attribute '%s' cannot be null.", d.Key),
+			)
 		}
 	} else {
 		truncValue := dVal.Value.Truncate(d.DecimalPlaces)
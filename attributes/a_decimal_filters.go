@@ -124,6 +124,7 @@ func (d Decimal) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 	if exists {
 		if len(nullStrings) != 1 {
 			return filters.Exception(
+				nullKey,
 				"Cannot null check attribute '%s' against more than one value.",
 				d.Key,
 			)
@@ -149,6 +150,7 @@ func (d Decimal) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 			})
 		} else {
 			return filters.Exception(
+				nullKey,
 				"Invalid null check value on attribute '%s'. Must be boolean.",
 				d.Key,
 			)
@@ -160,6 +162,7 @@ func (d Decimal) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 	if exists {
 		if len(exactStrings) != 1 {
 			return filters.Exception(
+				exactKey,
 				"Cannot compare attribute '%s' against more than one value.",
 				d.Key,
 			)
@@ -178,6 +181,7 @@ func (d Decimal) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 			})
 		} else {
 			return filters.Exception(
+				exactKey,
 				"Invalid exact value on attribute '%s'. Must be decimal.",
 				d.Key,
 			)
@@ -189,6 +193,7 @@ func (d Decimal) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 	if exists {
 		if len(lesserStrings) != 1 {
 			return filters.Exception(
+				lesserKey,
 				"Cannot compare attribute '%s' to be lesser than more than one value.",
 				d.Key,
 			)
@@ -208,6 +213,7 @@ func (d Decimal) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 			})
 		} else {
 			return filters.Exception(
+				lesserKey,
 				"Invalid lesser than comparison value on attribute '%s'. Must be decimal.",
 				d.Key,
 			)
@@ -219,6 +225,7 @@ func (d Decimal) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 	if exists {
 		if len(greaterStrings) != 1 {
 			return filters.Exception(
+				greaterKey,
 				"Cannot compare attribute '%s' to be greater than more than one value.",
 				d.Key,
 			)
@@ -238,6 +245,7 @@ func (d Decimal) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 			})
 		} else {
 			return filters.Exception(
+				greaterKey,
 				"Invalid greater than comparison value on attribute '%s'. Must be decimal.",
 				d.Key,
 			)
@@ -0,0 +1,113 @@
+package attributes
+
+import (
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"strings"
+)
+
+type Enum struct {
+	AttributeStub
+	Key        string
+	Access     string
+	ColumnName string
+	Nullable   bool
+	Choices    []string
+	Default    func(interface{}) EnumValue
+}
+
+func (e Enum) GetKey() string {
+	return e.Key
+}
+func (e Enum) GetAccess() string {
+	return e.Access
+}
+
+// the values the attribute accepts, listed in the parameter info
+func (e Enum) GetChoices() []string {
+	return e.Choices
+}
+func (e Enum) isChoice(value string) bool {
+	for _, choice := range e.Choices {
+		if value == choice {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Enum) GetSelectDirect() ([]string, []interface{}) {
+	var destination *string
+	return []string{e.ColumnName}, []interface{}{
+		&destination,
+	}
+}
+
+func (e Enum) WithColumn(column string) schema.Attribute {
+	e.ColumnName = column
+	return &e
+}
+
+func (e Enum) GetOrderMap() map[string]string {
+	orders := map[string]string{}
+	orders[e.Key] = e.ColumnName
+	return orders
+}
+
+func (e *Enum) DefaultFallback(val interface{}, instance interface{}) (interface{}, error) {
+	if val == nil || !AssertEnum(val).Provided {
+		if e.Default != nil {
+			return e.Default(instance), nil
+		}
+		return nil, nil
+	}
+	return AssertEnum(val), nil
+}
+func (e *Enum) Validate(val interface{}) (interface{}, error) {
+	eVal := AssertEnum(val)
+	if eVal.Value == nil {
+		if !e.Nullable {
+			return nil, utils.AttributeError(
+				e.Key,
+				utils.CODE_NULL,
+				fmt.Sprintf("Attribute '%s' cannot be null.", e.Key),
+			)
+		}
+	} else if !e.isChoice(*eVal.Value) {
+		return nil, utils.AttributeError(
+			e.Key,
+			utils.CODE_INVALID,
+			fmt.Sprintf(
+				"Attribute '%s' must be one of: %s.",
+				e.Key,
+				strings.Join(e.Choices, ", "),
+			),
+		)
+	}
+	return eVal, nil
+}
+func (e *Enum) ValidateUpdate(newVal interface{}, oldVal interface{}) (interface{}, error) {
+	newEnum := AssertEnum(newVal)
+	oldEnum := AssertEnum(oldVal)
+	if !newEnum.Provided {
+		return nil, nil
+	}
+	valid, err := e.Validate(newEnum)
+	if err != nil {
+		return nil, err
+	}
+	validNewEnum := AssertEnum(valid)
+	if validNewEnum.Equal(oldEnum) {
+		return nil, nil
+	}
+	return validNewEnum, nil
+}
+
+func (e *Enum) GetInsert(val interface{}) ([]string, []interface{}) {
+	eVal := AssertEnum(val)
+
+	columns := []string{e.ColumnName}
+	values := []interface{}{eVal.Value}
+	return columns, values
+}
@@ -0,0 +1,152 @@
+package attributes
+
+import (
+	"fmt"
+	"github.com/bor3ham/reja/filters"
+	"github.com/bor3ham/reja/schema"
+	"github.com/lib/pq"
+	"strings"
+)
+
+type EnumNullFilter struct {
+	*schema.BaseFilter
+	null   bool
+	column string
+}
+
+func (f EnumNullFilter) GetWhere(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) (
+	[]string,
+	[]interface{},
+) {
+	if f.null {
+		return []string{
+			fmt.Sprintf("%s is null", f.column),
+		}, []interface{}{}
+	} else {
+		return []string{
+			fmt.Sprintf("%s is not null", f.column),
+		}, []interface{}{}
+	}
+}
+
+type EnumExactFilter struct {
+	*schema.BaseFilter
+	matching []string
+	column   string
+}
+
+func (f EnumExactFilter) GetWhere(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) (
+	[]string,
+	[]interface{},
+) {
+	return []string{
+			fmt.Sprintf("%s = any($%d)", f.column, nextArg),
+		}, []interface{}{
+			pq.StringArray(f.matching),
+		}
+}
+
+func enumExamples(e Enum) []string {
+	examples := []string{}
+	for _, choice := range e.Choices {
+		examples = append(examples, fmt.Sprintf("?%s=%s", e.Key, choice))
+	}
+	return examples
+}
+
+func (e Enum) AvailableFilters() []interface{} {
+	return []interface{}{
+		filters.FilterDescription{
+			Key:         e.Key,
+			Description: "Exact match on enum value. Multiple values are matched as any of.",
+			Examples:    enumExamples(e),
+		},
+		filters.FilterDescription{
+			Key:         e.Key + filters.ISNULL_SUFFIX,
+			Description: "Whether enum value exists. Single value boolean.",
+			Examples: []string{
+				fmt.Sprintf("?%s=true", e.Key+filters.ISNULL_SUFFIX),
+				fmt.Sprintf("?%s=false", e.Key+filters.ISNULL_SUFFIX),
+			},
+		},
+	}
+}
+func (e Enum) ValidateFilters(queries map[string][]string) ([]schema.Filter, error) {
+	valids := []schema.Filter{}
+
+	nullKey := e.Key + filters.ISNULL_SUFFIX
+	nullStrings, exists := queries[nullKey]
+	if exists {
+		if len(nullStrings) != 1 {
+			return filters.Exception(
+				nullKey,
+				"Cannot null check attribute '%s' against more than one value.",
+				e.Key,
+			)
+		}
+		isNullString := strings.ToLower(nullStrings[0])
+		if isNullString == "true" {
+			valids = append(valids, EnumNullFilter{
+				BaseFilter: &schema.BaseFilter{
+					QArgKey:    nullKey,
+					QArgValues: []string{"true"},
+				},
+				null:   true,
+				column: e.ColumnName,
+			})
+		} else if isNullString == "false" {
+			valids = append(valids, EnumNullFilter{
+				BaseFilter: &schema.BaseFilter{
+					QArgKey:    nullKey,
+					QArgValues: []string{"false"},
+				},
+				null:   false,
+				column: e.ColumnName,
+			})
+		} else {
+			return filters.Exception(
+				nullKey,
+				"Invalid null check value on attribute '%s'. Must be boolean.",
+				e.Key,
+			)
+		}
+	}
+
+	exactKey := e.Key
+	exacts, exists := queries[exactKey]
+	if exists {
+		matching := []string{}
+		for _, exact := range exacts {
+			if !e.isChoice(exact) {
+				return filters.Exception(
+					exactKey,
+					"Invalid choice '%s' specified on attribute '%s'.",
+					exact,
+					e.Key,
+				)
+			}
+			matching = append(matching, exact)
+		}
+
+		valids = append(valids, EnumExactFilter{
+			BaseFilter: &schema.BaseFilter{
+				QArgKey:    exactKey,
+				QArgValues: matching,
+			},
+			matching: matching,
+			column:   e.ColumnName,
+		})
+	}
+
+	return valids, nil
+}
@@ -0,0 +1,51 @@
+package attributes
+
+import (
+	"encoding/json"
+)
+
+type EnumValue struct {
+	Value    *string
+	Provided bool
+}
+
+func (ev *EnumValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ev.Value)
+}
+func (ev *EnumValue) UnmarshalJSON(data []byte) error {
+	ev.Provided = true
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	var val string
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	ev.Value = &val
+	return nil
+}
+func (ev EnumValue) Equal(oev EnumValue) bool {
+	if ev.Value == nil {
+		return (oev.Value == nil)
+	} else if oev.Value == nil {
+		return false
+	}
+	return (*ev.Value == *oev.Value)
+}
+
+func AssertEnum(val interface{}) EnumValue {
+	eVal, ok := val.(EnumValue)
+	if !ok {
+		plainVal, ok := val.(**string)
+		if !ok {
+			panic("Bad enum value")
+		}
+		return EnumValue{
+			Value:    *plainVal,
+			Provided: true,
+		}
+	}
+	return eVal
+}
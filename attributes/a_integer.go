@@ -1,13 +1,15 @@
 package attributes
 
 import (
-	"errors"
 	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
 )
 
 type Integer struct {
 	AttributeStub
 	Key        string
+	Access     string
 	ColumnName string
 	Nullable   bool
 	Default    func(interface{}) IntegerValue
@@ -16,6 +18,9 @@ type Integer struct {
 func (i Integer) GetKey() string {
 	return i.Key
 }
+func (i Integer) GetAccess() string {
+	return i.Access
+}
 
 func (i Integer) GetSelectDirect() ([]string, []interface{}) {
 	var destination *int
@@ -24,6 +29,11 @@ func (i Integer) GetSelectDirect() ([]string, []interface{}) {
 	}
 }
 
+func (i Integer) WithColumn(column string) schema.Attribute {
+	i.ColumnName = column
+	return &i
+}
+
 func (i Integer) GetOrderMap() map[string]string {
 	orders := map[string]string{}
 	orders[i.Key] = i.ColumnName
@@ -31,20 +41,23 @@ func (i Integer) GetOrderMap() map[string]string {
 }
 
 func (i *Integer) DefaultFallback(val interface{}, instance interface{}) (interface{}, error) {
-	iVal := AssertInteger(val)
-	if !iVal.Provided {
+	if val == nil || !AssertInteger(val).Provided {
 		if i.Default != nil {
 			return i.Default(instance), nil
 		}
 		return nil, nil
 	}
-	return iVal, nil
+	return AssertInteger(val), nil
 }
 func (i *Integer) Validate(val interface{}) (interface{}, error) {
 	iVal := AssertInteger(val)
 	if iVal.Value == nil {
 		if !i.Nullable {
-			return nil, errors.New(fmt.Sprintf("Attribute '%s' cannot be null.", i.Key))
+			return nil, utils.AttributeError(
+				i.Key,
+				utils.CODE_NULL,
+				fmt.Sprintf("Attribute '%s' cannot be null.", i.Key),
+			)
 		}
 	}
 	return iVal, nil
@@ -0,0 +1,90 @@
+package attributes
+
+import (
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"github.com/lib/pq"
+)
+
+type IntegerArray struct {
+	AttributeStub
+	Key        string
+	Access     string
+	ColumnName string
+	Nullable   bool
+	Default    func(interface{}) IntegerArrayValue
+}
+
+func (i IntegerArray) GetKey() string {
+	return i.Key
+}
+func (i IntegerArray) GetAccess() string {
+	return i.Access
+}
+
+func (i IntegerArray) GetSelectDirect() ([]string, []interface{}) {
+	var destination *pq.Int64Array
+	return []string{i.ColumnName}, []interface{}{
+		&destination,
+	}
+}
+
+func (i IntegerArray) WithColumn(column string) schema.Attribute {
+	i.ColumnName = column
+	return &i
+}
+
+func (i *IntegerArray) DefaultFallback(val interface{}, instance interface{}) (interface{}, error) {
+	if val == nil || !AssertIntegerArray(val).Provided {
+		if i.Default != nil {
+			return i.Default(instance), nil
+		}
+		return nil, nil
+	}
+	return AssertIntegerArray(val), nil
+}
+func (i *IntegerArray) Validate(val interface{}) (interface{}, error) {
+	iaVal := AssertIntegerArray(val)
+	if iaVal.Value == nil {
+		if !i.Nullable {
+			return nil, utils.AttributeError(
+				i.Key,
+				utils.CODE_NULL,
+				fmt.Sprintf("Attribute '%s' cannot be null.", i.Key),
+			)
+		}
+	}
+	return iaVal, nil
+}
+func (i *IntegerArray) ValidateUpdate(newVal interface{}, oldVal interface{}) (interface{}, error) {
+	newArray := AssertIntegerArray(newVal)
+	oldArray := AssertIntegerArray(oldVal)
+	if !newArray.Provided {
+		return nil, nil
+	}
+	valid, err := i.Validate(newArray)
+	if err != nil {
+		return nil, err
+	}
+	validNewArray := AssertIntegerArray(valid)
+	if validNewArray.Equal(oldArray) {
+		return nil, nil
+	}
+	return validNewArray, nil
+}
+
+func (i *IntegerArray) GetInsert(val interface{}) ([]string, []interface{}) {
+	iaVal := AssertIntegerArray(val)
+
+	columns := []string{i.ColumnName}
+	values := []interface{}{nil}
+	if iaVal.Value != nil {
+		items := pq.Int64Array{}
+		for _, item := range *iaVal.Value {
+			items = append(items, int64(item))
+		}
+		values[0] = items
+	}
+	return columns, values
+}
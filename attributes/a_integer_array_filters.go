@@ -0,0 +1,191 @@
+package attributes
+
+import (
+	"fmt"
+	"github.com/bor3ham/reja/filters"
+	"github.com/bor3ham/reja/schema"
+	"github.com/lib/pq"
+	"strconv"
+	"strings"
+)
+
+type IntegerArrayNullFilter struct {
+	*schema.BaseFilter
+	null   bool
+	column string
+}
+
+func (f IntegerArrayNullFilter) GetWhere(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) (
+	[]string,
+	[]interface{},
+) {
+	if f.null {
+		return []string{
+			fmt.Sprintf("%s is null", f.column),
+		}, []interface{}{}
+	} else {
+		return []string{
+			fmt.Sprintf("%s is not null", f.column),
+		}, []interface{}{}
+	}
+}
+
+// contains matches arrays holding every item, overlaps any of them
+type IntegerArrayItemsFilter struct {
+	*schema.BaseFilter
+	items    []int64
+	overlaps bool
+	column   string
+}
+
+func (f IntegerArrayItemsFilter) GetWhere(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) (
+	[]string,
+	[]interface{},
+) {
+	operator := "@>"
+	if f.overlaps {
+		operator = "&&"
+	}
+	return []string{
+			fmt.Sprintf("%s %s $%d", f.column, operator, nextArg),
+		}, []interface{}{
+			pq.Int64Array(f.items),
+		}
+}
+
+func (i IntegerArray) AvailableFilters() []interface{} {
+	return []interface{}{
+		filters.FilterDescription{
+			Key:         i.Key + filters.ISNULL_SUFFIX,
+			Description: "Whether integer array value exists. Single value boolean.",
+			Examples: []string{
+				fmt.Sprintf("?%s=true", i.Key+filters.ISNULL_SUFFIX),
+				fmt.Sprintf("?%s=false", i.Key+filters.ISNULL_SUFFIX),
+			},
+		},
+		filters.FilterDescription{
+			Key:         i.Key + filters.CONTAINS_SUFFIX,
+			Description: "Integer array value contains every given item. Multiple values integer.",
+			Examples: []string{
+				fmt.Sprintf("?%s=1", i.Key+filters.CONTAINS_SUFFIX),
+				fmt.Sprintf("?%s=1&%s=2", i.Key+filters.CONTAINS_SUFFIX, i.Key+filters.CONTAINS_SUFFIX),
+			},
+		},
+		filters.FilterDescription{
+			Key:         i.Key + filters.OVERLAPS_SUFFIX,
+			Description: "Integer array value contains any given item. Multiple values integer.",
+			Examples: []string{
+				fmt.Sprintf("?%s=1&%s=2", i.Key+filters.OVERLAPS_SUFFIX, i.Key+filters.OVERLAPS_SUFFIX),
+			},
+		},
+	}
+}
+
+func parseIntegerItems(key string, attributeKey string, values []string) ([]int64, []string, error) {
+	items := []int64{}
+	strs := []string{}
+	for _, value := range values {
+		item, err := strconv.Atoi(value)
+		if err != nil {
+			_, err = filters.Exception(
+				key,
+				"Invalid integer item specified on attribute '%s'.",
+				attributeKey,
+			)
+			return nil, nil, err
+		}
+		items = append(items, int64(item))
+		strs = append(strs, strconv.Itoa(item))
+	}
+	return items, strs, nil
+}
+
+func (i IntegerArray) ValidateFilters(queries map[string][]string) ([]schema.Filter, error) {
+	valids := []schema.Filter{}
+
+	nullKey := i.Key + filters.ISNULL_SUFFIX
+	nullStrings, exists := queries[nullKey]
+	if exists {
+		if len(nullStrings) != 1 {
+			return filters.Exception(
+				nullKey,
+				"Cannot null check attribute '%s' against more than one value.",
+				i.Key,
+			)
+		}
+		isNullString := strings.ToLower(nullStrings[0])
+		if isNullString == "true" {
+			valids = append(valids, IntegerArrayNullFilter{
+				BaseFilter: &schema.BaseFilter{
+					QArgKey:    nullKey,
+					QArgValues: []string{"true"},
+				},
+				null:   true,
+				column: i.ColumnName,
+			})
+		} else if isNullString == "false" {
+			valids = append(valids, IntegerArrayNullFilter{
+				BaseFilter: &schema.BaseFilter{
+					QArgKey:    nullKey,
+					QArgValues: []string{"false"},
+				},
+				null:   false,
+				column: i.ColumnName,
+			})
+		} else {
+			return filters.Exception(
+				nullKey,
+				"Invalid null check value on attribute '%s'. Must be boolean.",
+				i.Key,
+			)
+		}
+	}
+
+	containsKey := i.Key + filters.CONTAINS_SUFFIX
+	contains, exists := queries[containsKey]
+	if exists {
+		items, strs, err := parseIntegerItems(containsKey, i.Key, contains)
+		if err != nil {
+			return []schema.Filter{}, err
+		}
+		valids = append(valids, IntegerArrayItemsFilter{
+			BaseFilter: &schema.BaseFilter{
+				QArgKey:    containsKey,
+				QArgValues: strs,
+			},
+			items:    items,
+			overlaps: false,
+			column:   i.ColumnName,
+		})
+	}
+
+	overlapsKey := i.Key + filters.OVERLAPS_SUFFIX
+	overlaps, exists := queries[overlapsKey]
+	if exists {
+		items, strs, err := parseIntegerItems(overlapsKey, i.Key, overlaps)
+		if err != nil {
+			return []schema.Filter{}, err
+		}
+		valids = append(valids, IntegerArrayItemsFilter{
+			BaseFilter: &schema.BaseFilter{
+				QArgKey:    overlapsKey,
+				QArgValues: strs,
+			},
+			items:    items,
+			overlaps: true,
+			column:   i.ColumnName,
+		})
+	}
+
+	return valids, nil
+}
@@ -0,0 +1,69 @@
+package attributes
+
+import (
+	"encoding/json"
+	"github.com/lib/pq"
+)
+
+type IntegerArrayValue struct {
+	Value    *[]int
+	Provided bool
+}
+
+func (iav *IntegerArrayValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(iav.Value)
+}
+func (iav *IntegerArrayValue) UnmarshalJSON(data []byte) error {
+	iav.Provided = true
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	val := []int{}
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	iav.Value = &val
+	return nil
+}
+func (iav IntegerArrayValue) Equal(oiav IntegerArrayValue) bool {
+	if iav.Value == nil {
+		return (oiav.Value == nil)
+	} else if oiav.Value == nil {
+		return false
+	}
+	if len(*iav.Value) != len(*oiav.Value) {
+		return false
+	}
+	for index, item := range *iav.Value {
+		if item != (*oiav.Value)[index] {
+			return false
+		}
+	}
+	return true
+}
+
+func AssertIntegerArray(val interface{}) IntegerArrayValue {
+	iaVal, ok := val.(IntegerArrayValue)
+	if !ok {
+		plainVal, ok := val.(**pq.Int64Array)
+		if !ok {
+			panic("Bad integer array value")
+		}
+		if *plainVal == nil {
+			return IntegerArrayValue{
+				Provided: true,
+			}
+		}
+		items := []int{}
+		for _, item := range **plainVal {
+			items = append(items, int(item))
+		}
+		return IntegerArrayValue{
+			Value:    &items,
+			Provided: true,
+		}
+	}
+	return iaVal
+}
@@ -124,6 +124,7 @@ func (i Integer) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 	if exists {
 		if len(nullStrings) != 1 {
 			return filters.Exception(
+				nullKey,
 				"Cannot null check attribute '%s' against more than one value.",
 				i.Key,
 			)
@@ -149,6 +150,7 @@ func (i Integer) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 			})
 		} else {
 			return filters.Exception(
+				nullKey,
 				"Invalid null check value on attribute '%s'. Must be boolean.",
 				i.Key,
 			)
@@ -160,6 +162,7 @@ func (i Integer) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 	if exists {
 		if len(exactStrings) != 1 {
 			return filters.Exception(
+				exactKey,
 				"Cannot compare attribute '%s' against more than one value.",
 				i.Key,
 			)
@@ -177,6 +180,7 @@ func (i Integer) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 			})
 		} else {
 			return filters.Exception(
+				exactKey,
 				"Invalid exact value on attribute '%s'. Must be integer.",
 				i.Key,
 			)
@@ -188,6 +192,7 @@ func (i Integer) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 	if exists {
 		if len(lesserStrings) != 1 {
 			return filters.Exception(
+				lesserKey,
 				"Cannot compare attribute '%s' to be lesser than more than one value.",
 				i.Key,
 			)
@@ -206,6 +211,7 @@ func (i Integer) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 			})
 		} else {
 			return filters.Exception(
+				lesserKey,
 				"Invalid lesser than comparison value on attribute '%s'. Must be integer.",
 				i.Key,
 			)
@@ -217,6 +223,7 @@ func (i Integer) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 	if exists {
 		if len(greaterStrings) != 1 {
 			return filters.Exception(
+				greaterKey,
 				"Cannot compare attribute '%s' to be greater than more than one value.",
 				i.Key,
 			)
@@ -235,6 +242,7 @@ func (i Integer) ValidateFilters(queries map[string][]string) ([]schema.Filter,
 			})
 		} else {
 			return filters.Exception(
+				greaterKey,
 				"Invalid greater than comparison value on attribute '%s'. Must be integer.",
 				i.Key,
 			)
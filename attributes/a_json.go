@@ -0,0 +1,92 @@
+package attributes
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+)
+
+// an arbitrary json document, stored in a json or jsonb column
+type JSON struct {
+	AttributeStub
+	Key        string
+	Access     string
+	ColumnName string
+	Nullable   bool
+	Default    func(interface{}) JSONValue
+}
+
+func (j JSON) GetKey() string {
+	return j.Key
+}
+func (j JSON) GetAccess() string {
+	return j.Access
+}
+
+func (j JSON) GetSelectDirect() ([]string, []interface{}) {
+	var destination *[]byte
+	return []string{j.ColumnName}, []interface{}{
+		&destination,
+	}
+}
+
+func (j JSON) WithColumn(column string) schema.Attribute {
+	j.ColumnName = column
+	return &j
+}
+
+func (j *JSON) DefaultFallback(val interface{}, instance interface{}) (interface{}, error) {
+	if val == nil || !AssertJSON(val).Provided {
+		if j.Default != nil {
+			return j.Default(instance), nil
+		}
+		return nil, nil
+	}
+	return AssertJSON(val), nil
+}
+func (j *JSON) Validate(val interface{}) (interface{}, error) {
+	jVal := AssertJSON(val)
+	if jVal.Value == nil {
+		if !j.Nullable {
+			return nil, utils.AttributeError(
+				j.Key,
+				utils.CODE_NULL,
+				fmt.Sprintf("Attribute '%s' cannot be null.", j.Key),
+			)
+		}
+	}
+	return jVal, nil
+}
+func (j *JSON) ValidateUpdate(newVal interface{}, oldVal interface{}) (interface{}, error) {
+	newJSON := AssertJSON(newVal)
+	oldJSON := AssertJSON(oldVal)
+	if !newJSON.Provided {
+		return nil, nil
+	}
+	valid, err := j.Validate(newJSON)
+	if err != nil {
+		return nil, err
+	}
+	validNewJSON := AssertJSON(valid)
+	if validNewJSON.Equal(oldJSON) {
+		return nil, nil
+	}
+	return validNewJSON, nil
+}
+
+func (j *JSON) GetInsert(val interface{}) ([]string, []interface{}) {
+	jVal := AssertJSON(val)
+
+	columns := []string{j.ColumnName}
+	values := []interface{}{nil}
+	if jVal.Value != nil {
+		blob, err := json.Marshal(jVal.Value)
+		if err != nil {
+			panic(err)
+		}
+		// sent as text, bytes would be encoded as bytea
+		values[0] = string(blob)
+	}
+	return columns, values
+}
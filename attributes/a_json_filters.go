@@ -0,0 +1,223 @@
+package attributes
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/bor3ham/reja/filters"
+	"github.com/bor3ham/reja/schema"
+	"github.com/lib/pq"
+	"sort"
+	"strings"
+)
+
+type JSONNullFilter struct {
+	*schema.BaseFilter
+	null   bool
+	column string
+}
+
+func (f JSONNullFilter) GetWhere(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) (
+	[]string,
+	[]interface{},
+) {
+	if f.null {
+		return []string{
+			fmt.Sprintf("%s is null", f.column),
+		}, []interface{}{}
+	} else {
+		return []string{
+			fmt.Sprintf("%s is not null", f.column),
+		}, []interface{}{}
+	}
+}
+
+// matches the text of the value found at a path within the document
+type JSONPathFilter struct {
+	*schema.BaseFilter
+	path     []string
+	matching []string
+	column   string
+}
+
+func (f JSONPathFilter) GetWhere(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) (
+	[]string,
+	[]interface{},
+) {
+	return []string{
+			fmt.Sprintf("%s #>> $%d = any($%d)", f.column, nextArg, nextArg+1),
+		}, []interface{}{
+			pq.StringArray(f.path),
+			pq.StringArray(f.matching),
+		}
+}
+
+type JSONContainsFilter struct {
+	*schema.BaseFilter
+	document string
+	column   string
+}
+
+func (f JSONContainsFilter) GetWhere(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) (
+	[]string,
+	[]interface{},
+) {
+	return []string{
+			fmt.Sprintf("%s::jsonb @> $%d::jsonb", f.column, nextArg),
+		}, []interface{}{
+			f.document,
+		}
+}
+
+func (j JSON) AvailableFilters() []interface{} {
+	return []interface{}{
+		filters.FilterDescription{
+			Key:         j.Key + filters.ISNULL_SUFFIX,
+			Description: "Whether json value exists. Single value boolean.",
+			Examples: []string{
+				fmt.Sprintf("?%s=true", j.Key+filters.ISNULL_SUFFIX),
+				fmt.Sprintf("?%s=false", j.Key+filters.ISNULL_SUFFIX),
+			},
+		},
+		filters.FilterDescription{
+			Key:         j.Key + filters.PATH_SUFFIX + "[path]",
+			Description: "Exact match on the value at a dot separated path. Multiple values are matched as any of.",
+			Examples: []string{
+				fmt.Sprintf("?%s[a.b]=foo", j.Key+filters.PATH_SUFFIX),
+				fmt.Sprintf("?%s[tags.0]=foo", j.Key+filters.PATH_SUFFIX),
+			},
+		},
+		filters.FilterDescription{
+			Key:         j.Key + filters.CONTAINS_SUFFIX,
+			Description: "Json value contains the given document. Single value json.",
+			Examples: []string{
+				fmt.Sprintf(`?%s={"a":{"b":"foo"}}`, j.Key+filters.CONTAINS_SUFFIX),
+			},
+		},
+	}
+}
+func (j JSON) ValidateFilters(queries map[string][]string) ([]schema.Filter, error) {
+	valids := []schema.Filter{}
+
+	nullKey := j.Key + filters.ISNULL_SUFFIX
+	nullStrings, exists := queries[nullKey]
+	if exists {
+		if len(nullStrings) != 1 {
+			return filters.Exception(
+				nullKey,
+				"Cannot null check attribute '%s' against more than one value.",
+				j.Key,
+			)
+		}
+		isNullString := strings.ToLower(nullStrings[0])
+		if isNullString == "true" {
+			valids = append(valids, JSONNullFilter{
+				BaseFilter: &schema.BaseFilter{
+					QArgKey:    nullKey,
+					QArgValues: []string{"true"},
+				},
+				null:   true,
+				column: j.ColumnName,
+			})
+		} else if isNullString == "false" {
+			valids = append(valids, JSONNullFilter{
+				BaseFilter: &schema.BaseFilter{
+					QArgKey:    nullKey,
+					QArgValues: []string{"false"},
+				},
+				null:   false,
+				column: j.ColumnName,
+			})
+		} else {
+			return filters.Exception(
+				nullKey,
+				"Invalid null check value on attribute '%s'. Must be boolean.",
+				j.Key,
+			)
+		}
+	}
+
+	// paths are part of the key, so every matching key is a separate filter
+	pathPrefix := j.Key + filters.PATH_SUFFIX + "["
+	pathKeys := []string{}
+	for key := range queries {
+		if strings.HasPrefix(key, pathPrefix) {
+			pathKeys = append(pathKeys, key)
+		}
+	}
+	sort.Strings(pathKeys)
+	for _, pathKey := range pathKeys {
+		pathString := strings.TrimPrefix(pathKey, pathPrefix)
+		if !strings.HasSuffix(pathString, "]") {
+			return filters.Exception(
+				pathKey,
+				"Invalid path specified on attribute '%s'. Must be enclosed in brackets.",
+				j.Key,
+			)
+		}
+		path := strings.Split(strings.TrimSuffix(pathString, "]"), ".")
+		for _, segment := range path {
+			if len(segment) == 0 {
+				return filters.Exception(
+					pathKey,
+					"Invalid path specified on attribute '%s'. Cannot contain empty segments.",
+					j.Key,
+				)
+			}
+		}
+
+		valids = append(valids, JSONPathFilter{
+			BaseFilter: &schema.BaseFilter{
+				QArgKey:    pathKey,
+				QArgValues: queries[pathKey],
+			},
+			path:     path,
+			matching: queries[pathKey],
+			column:   j.ColumnName,
+		})
+	}
+
+	containsKey := j.Key + filters.CONTAINS_SUFFIX
+	contains, exists := queries[containsKey]
+	if exists {
+		if len(contains) != 1 {
+			return filters.Exception(
+				containsKey,
+				"Cannot contains match attribute '%s' to more than one document.",
+				j.Key,
+			)
+		}
+		if !json.Valid([]byte(contains[0])) {
+			return filters.Exception(
+				containsKey,
+				"Invalid json document specified on attribute '%s'.",
+				j.Key,
+			)
+		}
+
+		valids = append(valids, JSONContainsFilter{
+			BaseFilter: &schema.BaseFilter{
+				QArgKey:    containsKey,
+				QArgValues: contains,
+			},
+			document: contains[0],
+			column:   j.ColumnName,
+		})
+	}
+
+	return valids, nil
+}
@@ -0,0 +1,92 @@
+package attributes
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// json null is stored as a null column
+type JSONValue struct {
+	Value    interface{}
+	Provided bool
+}
+
+func decodeJSON(data []byte) (interface{}, error) {
+	var val interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// keeps large and precise numbers intact
+	decoder.UseNumber()
+	err := decoder.Decode(&val)
+	return val, err
+}
+
+func (jv *JSONValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jv.Value)
+}
+func (jv *JSONValue) UnmarshalJSON(data []byte) error {
+	jv.Provided = true
+
+	val, err := decodeJSON(data)
+	if err != nil {
+		return err
+	}
+	jv.Value = val
+	return nil
+}
+func (jv JSONValue) Equal(ojv JSONValue) bool {
+	if jv.Value == nil {
+		return (ojv.Value == nil)
+	} else if ojv.Value == nil {
+		return false
+	}
+	// object keys are marshalled in order, so equal documents marshal the same
+	blob, err := json.Marshal(jv.Value)
+	if err != nil {
+		return false
+	}
+	oblob, err := json.Marshal(ojv.Value)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(blob, oblob)
+}
+
+// nested documents are cached as json rather than registering every type with gob
+func (jv JSONValue) GobEncode() ([]byte, error) {
+	if !jv.Provided {
+		return []byte{}, nil
+	}
+	return json.Marshal(jv.Value)
+}
+func (jv *JSONValue) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		jv.Value = nil
+		jv.Provided = false
+		return nil
+	}
+	return jv.UnmarshalJSON(data)
+}
+
+func AssertJSON(val interface{}) JSONValue {
+	jVal, ok := val.(JSONValue)
+	if !ok {
+		plainVal, ok := val.(**[]byte)
+		if !ok {
+			panic("Bad json value")
+		}
+		if *plainVal == nil {
+			return JSONValue{
+				Provided: true,
+			}
+		}
+		value, err := decodeJSON(**plainVal)
+		if err != nil {
+			panic(err)
+		}
+		return JSONValue{
+			Value:    value,
+			Provided: true,
+		}
+	}
+	return jVal
+}
@@ -1,14 +1,16 @@
 package attributes
 
 import (
-	"errors"
 	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
 	"strings"
 )
 
 type Text struct {
 	AttributeStub
 	Key        string
+	Access     string
 	ColumnName string
 	Nullable   bool
 	MinLength  *int
@@ -19,6 +21,9 @@ type Text struct {
 func (t Text) GetKey() string {
 	return t.Key
 }
+func (t Text) GetAccess() string {
+	return t.Access
+}
 
 func (t Text) GetSelectDirect() ([]string, []interface{}) {
 	var destination *string
@@ -27,6 +32,11 @@ func (t Text) GetSelectDirect() ([]string, []interface{}) {
 	}
 }
 
+func (t Text) WithColumn(column string) schema.Attribute {
+	t.ColumnName = column
+	return &t
+}
+
 func (t Text) GetOrderMap() map[string]string {
 	orders := map[string]string{}
 	orders[t.Key] = t.ColumnName
@@ -46,26 +56,30 @@ func (t *Text) Validate(val interface{}) (interface{}, error) {
 	textVal := AssertText(val)
 	if textVal.Value == nil {
 		if !t.Nullable {
-			return textVal, errors.New(fmt.Sprintf("Attribute '%s' cannot be null.", t.Key))
+			return textVal, utils.AttributeError(
+				t.Key,
+				utils.CODE_NULL,
+				fmt.Sprintf("Attribute '%s' cannot be null.", t.Key),
+			)
 		}
 	} else {
 		trimmedValue := strings.TrimSpace(*textVal.Value)
 		textVal.Value = &trimmedValue
 		if t.MinLength != nil && len(*textVal.Value) < *t.MinLength {
 			if *t.MinLength == 1 {
-				return nil, errors.New(fmt.Sprintf(
+				return nil, utils.AttributeError(t.Key, utils.CODE_BLANK, fmt.Sprintf(
 					"Attribute '%s' cannot be blank.",
 					t.Key,
 				))
 			}
-			return nil, errors.New(fmt.Sprintf(
+			return nil, utils.AttributeError(t.Key, utils.CODE_TOO_SHORT, fmt.Sprintf(
 				"Attribute '%s' must be more than %d characters long.",
 				t.Key,
 				*t.MinLength,
 			))
 		}
 		if t.MaxLength != nil && len(*textVal.Value) > *t.MaxLength {
-			return nil, errors.New(fmt.Sprintf(
+			return nil, utils.AttributeError(t.Key, utils.CODE_TOO_LONG, fmt.Sprintf(
 				"Attribute '%s' must be fewer than %d characters long.",
 				t.Key,
 				*t.MaxLength,
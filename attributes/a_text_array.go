@@ -0,0 +1,86 @@
+package attributes
+
+import (
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"github.com/lib/pq"
+)
+
+type TextArray struct {
+	AttributeStub
+	Key        string
+	Access     string
+	ColumnName string
+	Nullable   bool
+	Default    func(interface{}) TextArrayValue
+}
+
+func (t TextArray) GetKey() string {
+	return t.Key
+}
+func (t TextArray) GetAccess() string {
+	return t.Access
+}
+
+func (t TextArray) GetSelectDirect() ([]string, []interface{}) {
+	var destination *pq.StringArray
+	return []string{t.ColumnName}, []interface{}{
+		&destination,
+	}
+}
+
+func (t TextArray) WithColumn(column string) schema.Attribute {
+	t.ColumnName = column
+	return &t
+}
+
+func (t *TextArray) DefaultFallback(val interface{}, instance interface{}) (interface{}, error) {
+	if val == nil || !AssertTextArray(val).Provided {
+		if t.Default != nil {
+			return t.Default(instance), nil
+		}
+		return nil, nil
+	}
+	return AssertTextArray(val), nil
+}
+func (t *TextArray) Validate(val interface{}) (interface{}, error) {
+	taVal := AssertTextArray(val)
+	if taVal.Value == nil {
+		if !t.Nullable {
+			return nil, utils.AttributeError(
+				t.Key,
+				utils.CODE_NULL,
+				fmt.Sprintf("Attribute '%s' cannot be null.", t.Key),
+			)
+		}
+	}
+	return taVal, nil
+}
+func (t *TextArray) ValidateUpdate(newVal interface{}, oldVal interface{}) (interface{}, error) {
+	newArray := AssertTextArray(newVal)
+	oldArray := AssertTextArray(oldVal)
+	if !newArray.Provided {
+		return nil, nil
+	}
+	valid, err := t.Validate(newArray)
+	if err != nil {
+		return nil, err
+	}
+	validNewArray := AssertTextArray(valid)
+	if validNewArray.Equal(oldArray) {
+		return nil, nil
+	}
+	return validNewArray, nil
+}
+
+func (t *TextArray) GetInsert(val interface{}) ([]string, []interface{}) {
+	taVal := AssertTextArray(val)
+
+	columns := []string{t.ColumnName}
+	values := []interface{}{nil}
+	if taVal.Value != nil {
+		values[0] = pq.StringArray(*taVal.Value)
+	}
+	return columns, values
+}
@@ -0,0 +1,162 @@
+package attributes
+
+import (
+	"fmt"
+	"github.com/bor3ham/reja/filters"
+	"github.com/bor3ham/reja/schema"
+	"github.com/lib/pq"
+	"strings"
+)
+
+type TextArrayNullFilter struct {
+	*schema.BaseFilter
+	null   bool
+	column string
+}
+
+func (f TextArrayNullFilter) GetWhere(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) (
+	[]string,
+	[]interface{},
+) {
+	if f.null {
+		return []string{
+			fmt.Sprintf("%s is null", f.column),
+		}, []interface{}{}
+	} else {
+		return []string{
+			fmt.Sprintf("%s is not null", f.column),
+		}, []interface{}{}
+	}
+}
+
+// contains matches arrays holding every item, overlaps any of them
+type TextArrayItemsFilter struct {
+	*schema.BaseFilter
+	items    []string
+	overlaps bool
+	column   string
+}
+
+func (f TextArrayItemsFilter) GetWhere(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) (
+	[]string,
+	[]interface{},
+) {
+	operator := "@>"
+	if f.overlaps {
+		operator = "&&"
+	}
+	return []string{
+			fmt.Sprintf("%s %s $%d", f.column, operator, nextArg),
+		}, []interface{}{
+			pq.StringArray(f.items),
+		}
+}
+
+func (t TextArray) AvailableFilters() []interface{} {
+	return []interface{}{
+		filters.FilterDescription{
+			Key:         t.Key + filters.ISNULL_SUFFIX,
+			Description: "Whether text array value exists. Single value boolean.",
+			Examples: []string{
+				fmt.Sprintf("?%s=true", t.Key+filters.ISNULL_SUFFIX),
+				fmt.Sprintf("?%s=false", t.Key+filters.ISNULL_SUFFIX),
+			},
+		},
+		filters.FilterDescription{
+			Key:         t.Key + filters.CONTAINS_SUFFIX,
+			Description: "Text array value contains every given item. Multiple values case sensitive freeform text.",
+			Examples: []string{
+				fmt.Sprintf("?%s=foo", t.Key+filters.CONTAINS_SUFFIX),
+				fmt.Sprintf("?%s=foo&%s=bar", t.Key+filters.CONTAINS_SUFFIX, t.Key+filters.CONTAINS_SUFFIX),
+			},
+		},
+		filters.FilterDescription{
+			Key:         t.Key + filters.OVERLAPS_SUFFIX,
+			Description: "Text array value contains any given item. Multiple values case sensitive freeform text.",
+			Examples: []string{
+				fmt.Sprintf("?%s=foo&%s=bar", t.Key+filters.OVERLAPS_SUFFIX, t.Key+filters.OVERLAPS_SUFFIX),
+			},
+		},
+	}
+}
+func (t TextArray) ValidateFilters(queries map[string][]string) ([]schema.Filter, error) {
+	valids := []schema.Filter{}
+
+	nullKey := t.Key + filters.ISNULL_SUFFIX
+	nullStrings, exists := queries[nullKey]
+	if exists {
+		if len(nullStrings) != 1 {
+			return filters.Exception(
+				nullKey,
+				"Cannot null check attribute '%s' against more than one value.",
+				t.Key,
+			)
+		}
+		isNullString := strings.ToLower(nullStrings[0])
+		if isNullString == "true" {
+			valids = append(valids, TextArrayNullFilter{
+				BaseFilter: &schema.BaseFilter{
+					QArgKey:    nullKey,
+					QArgValues: []string{"true"},
+				},
+				null:   true,
+				column: t.ColumnName,
+			})
+		} else if isNullString == "false" {
+			valids = append(valids, TextArrayNullFilter{
+				BaseFilter: &schema.BaseFilter{
+					QArgKey:    nullKey,
+					QArgValues: []string{"false"},
+				},
+				null:   false,
+				column: t.ColumnName,
+			})
+		} else {
+			return filters.Exception(
+				nullKey,
+				"Invalid null check value on attribute '%s'. Must be boolean.",
+				t.Key,
+			)
+		}
+	}
+
+	containsKey := t.Key + filters.CONTAINS_SUFFIX
+	contains, exists := queries[containsKey]
+	if exists {
+		valids = append(valids, TextArrayItemsFilter{
+			BaseFilter: &schema.BaseFilter{
+				QArgKey:    containsKey,
+				QArgValues: contains,
+			},
+			items:    contains,
+			overlaps: false,
+			column:   t.ColumnName,
+		})
+	}
+
+	overlapsKey := t.Key + filters.OVERLAPS_SUFFIX
+	overlaps, exists := queries[overlapsKey]
+	if exists {
+		valids = append(valids, TextArrayItemsFilter{
+			BaseFilter: &schema.BaseFilter{
+				QArgKey:    overlapsKey,
+				QArgValues: overlaps,
+			},
+			items:    overlaps,
+			overlaps: true,
+			column:   t.ColumnName,
+		})
+	}
+
+	return valids, nil
+}
@@ -0,0 +1,69 @@
+package attributes
+
+import (
+	"encoding/json"
+	"github.com/lib/pq"
+)
+
+type TextArrayValue struct {
+	Value    *[]string
+	Provided bool
+}
+
+func (tav *TextArrayValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tav.Value)
+}
+func (tav *TextArrayValue) UnmarshalJSON(data []byte) error {
+	tav.Provided = true
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	val := []string{}
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	tav.Value = &val
+	return nil
+}
+func (tav TextArrayValue) Equal(otav TextArrayValue) bool {
+	if tav.Value == nil {
+		return (otav.Value == nil)
+	} else if otav.Value == nil {
+		return false
+	}
+	if len(*tav.Value) != len(*otav.Value) {
+		return false
+	}
+	for index, item := range *tav.Value {
+		if item != (*otav.Value)[index] {
+			return false
+		}
+	}
+	return true
+}
+
+func AssertTextArray(val interface{}) TextArrayValue {
+	taVal, ok := val.(TextArrayValue)
+	if !ok {
+		plainVal, ok := val.(**pq.StringArray)
+		if !ok {
+			panic("Bad text array value")
+		}
+		if *plainVal == nil {
+			return TextArrayValue{
+				Provided: true,
+			}
+		}
+		items := []string(**plainVal)
+		if items == nil {
+			items = []string{}
+		}
+		return TextArrayValue{
+			Value:    &items,
+			Provided: true,
+		}
+	}
+	return taVal
+}
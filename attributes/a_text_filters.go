@@ -217,6 +217,7 @@ func (t Text) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 	if exists {
 		if len(nullStrings) != 1 {
 			return filters.Exception(
+				nullKey,
 				"Cannot null check attribute '%s' against more than one value.",
 				t.Key,
 			)
@@ -242,6 +243,7 @@ func (t Text) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 			})
 		} else {
 			return filters.Exception(
+				nullKey,
 				"Invalid null check value on attribute '%s'. Must be boolean.",
 				t.Key,
 			)
@@ -253,6 +255,7 @@ func (t Text) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 	if exists {
 		if len(exacts) != 1 {
 			return filters.Exception(
+				exactKey,
 				"Cannot exact match attribute '%s' to more than one value.",
 				t.Key,
 			)
@@ -273,6 +276,7 @@ func (t Text) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 	if exists {
 		if len(lengths) != 1 {
 			return filters.Exception(
+				lengthKey,
 				"Cannot length compare attribute '%s' to more than one length.",
 				t.Key,
 			)
@@ -282,6 +286,7 @@ func (t Text) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 		lengthInt, err := strconv.Atoi(length)
 		if err != nil {
 			return filters.Exception(
+				lengthKey,
 				"Invalid length match specified on attribute '%s'.",
 				t.Key,
 			)
@@ -320,6 +325,7 @@ func (t Text) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 	if exists {
 		if len(lts) != 1 {
 			return filters.Exception(
+				lesserKey,
 				"Cannot compare length of attribute '%s' to more than one value.",
 				t.Key,
 			)
@@ -329,6 +335,7 @@ func (t Text) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 		ltInt, err := strconv.Atoi(lt)
 		if err != nil || ltInt < 1 {
 			return filters.Exception(
+				lesserKey,
 				"Invalid length comparison specified on attribute '%s'.",
 				t.Key,
 			)
@@ -349,6 +356,7 @@ func (t Text) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 	if exists {
 		if len(gts) != 1 {
 			return filters.Exception(
+				greaterKey,
 				"Cannot compare length of attribute '%s' to more than one value.",
 				t.Key,
 			)
@@ -358,6 +366,7 @@ func (t Text) ValidateFilters(queries map[string][]string) ([]schema.Filter, err
 		gtInt, err := strconv.Atoi(gt)
 		if err != nil {
 			return filters.Exception(
+				greaterKey,
 				"Invalid length comparison specified on attribute '%s'.",
 				t.Key,
 			)
@@ -0,0 +1,108 @@
+package attributes
+
+import (
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"regexp"
+	"strings"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// the canonical lowercase form of a uuid, or false if it is not one
+func normaliseUUID(value string) (string, bool) {
+	normal := strings.ToLower(strings.TrimSpace(value))
+	return normal, uuidPattern.MatchString(normal)
+}
+
+type UUID struct {
+	AttributeStub
+	Key        string
+	Access     string
+	ColumnName string
+	Nullable   bool
+	Default    func(interface{}) UUIDValue
+}
+
+func (u UUID) GetKey() string {
+	return u.Key
+}
+func (u UUID) GetAccess() string {
+	return u.Access
+}
+
+func (u UUID) GetSelectDirect() ([]string, []interface{}) {
+	var destination *string
+	return []string{u.ColumnName}, []interface{}{
+		&destination,
+	}
+}
+
+func (u UUID) WithColumn(column string) schema.Attribute {
+	u.ColumnName = column
+	return &u
+}
+
+func (u UUID) GetOrderMap() map[string]string {
+	orders := map[string]string{}
+	orders[u.Key] = u.ColumnName
+	return orders
+}
+
+func (u *UUID) DefaultFallback(val interface{}, instance interface{}) (interface{}, error) {
+	if val == nil || !AssertUUID(val).Provided {
+		if u.Default != nil {
+			return u.Default(instance), nil
+		}
+		return nil, nil
+	}
+	return AssertUUID(val), nil
+}
+func (u *UUID) Validate(val interface{}) (interface{}, error) {
+	uVal := AssertUUID(val)
+	if uVal.Value == nil {
+		if !u.Nullable {
+			return nil, utils.AttributeError(
+				u.Key,
+				utils.CODE_NULL,
+				fmt.Sprintf("Attribute '%s' cannot be null.", u.Key),
+			)
+		}
+	} else {
+		normal, valid := normaliseUUID(*uVal.Value)
+		if !valid {
+			return nil, utils.AttributeError(
+				u.Key,
+				utils.CODE_INVALID,
+				fmt.Sprintf("Attribute '%s' must be a valid UUID.", u.Key),
+			)
+		}
+		uVal.Value = &normal
+	}
+	return uVal, nil
+}
+func (u *UUID) ValidateUpdate(newVal interface{}, oldVal interface{}) (interface{}, error) {
+	newUUID := AssertUUID(newVal)
+	oldUUID := AssertUUID(oldVal)
+	if !newUUID.Provided {
+		return nil, nil
+	}
+	valid, err := u.Validate(newUUID)
+	if err != nil {
+		return nil, err
+	}
+	validNewUUID := AssertUUID(valid)
+	if validNewUUID.Equal(oldUUID) {
+		return nil, nil
+	}
+	return validNewUUID, nil
+}
+
+func (u *UUID) GetInsert(val interface{}) ([]string, []interface{}) {
+	uVal := AssertUUID(val)
+
+	columns := []string{u.ColumnName}
+	values := []interface{}{uVal.Value}
+	return columns, values
+}
@@ -0,0 +1,146 @@
+package attributes
+
+import (
+	"fmt"
+	"github.com/bor3ham/reja/filters"
+	"github.com/bor3ham/reja/schema"
+	"github.com/lib/pq"
+	"strings"
+)
+
+type UUIDNullFilter struct {
+	*schema.BaseFilter
+	null   bool
+	column string
+}
+
+func (f UUIDNullFilter) GetWhere(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) (
+	[]string,
+	[]interface{},
+) {
+	if f.null {
+		return []string{
+			fmt.Sprintf("%s is null", f.column),
+		}, []interface{}{}
+	} else {
+		return []string{
+			fmt.Sprintf("%s is not null", f.column),
+		}, []interface{}{}
+	}
+}
+
+type UUIDExactFilter struct {
+	*schema.BaseFilter
+	matching []string
+	column   string
+}
+
+func (f UUIDExactFilter) GetWhere(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) (
+	[]string,
+	[]interface{},
+) {
+	return []string{
+			fmt.Sprintf("%s = any($%d)", f.column, nextArg),
+		}, []interface{}{
+			pq.StringArray(f.matching),
+		}
+}
+
+func (u UUID) AvailableFilters() []interface{} {
+	return []interface{}{
+		filters.FilterDescription{
+			Key:         u.Key,
+			Description: "Exact match on uuid value. Multiple values are matched as any of.",
+			Examples: []string{
+				fmt.Sprintf("?%s=9f3c5a8e-2b1d-4c6f-8e7a-0d4b2c1f6e9a", u.Key),
+			},
+		},
+		filters.FilterDescription{
+			Key:         u.Key + filters.ISNULL_SUFFIX,
+			Description: "Whether uuid value exists. Single value boolean.",
+			Examples: []string{
+				fmt.Sprintf("?%s=true", u.Key+filters.ISNULL_SUFFIX),
+				fmt.Sprintf("?%s=false", u.Key+filters.ISNULL_SUFFIX),
+			},
+		},
+	}
+}
+func (u UUID) ValidateFilters(queries map[string][]string) ([]schema.Filter, error) {
+	valids := []schema.Filter{}
+
+	nullKey := u.Key + filters.ISNULL_SUFFIX
+	nullStrings, exists := queries[nullKey]
+	if exists {
+		if len(nullStrings) != 1 {
+			return filters.Exception(
+				nullKey,
+				"Cannot null check attribute '%s' against more than one value.",
+				u.Key,
+			)
+		}
+		isNullString := strings.ToLower(nullStrings[0])
+		if isNullString == "true" {
+			valids = append(valids, UUIDNullFilter{
+				BaseFilter: &schema.BaseFilter{
+					QArgKey:    nullKey,
+					QArgValues: []string{"true"},
+				},
+				null:   true,
+				column: u.ColumnName,
+			})
+		} else if isNullString == "false" {
+			valids = append(valids, UUIDNullFilter{
+				BaseFilter: &schema.BaseFilter{
+					QArgKey:    nullKey,
+					QArgValues: []string{"false"},
+				},
+				null:   false,
+				column: u.ColumnName,
+			})
+		} else {
+			return filters.Exception(
+				nullKey,
+				"Invalid null check value on attribute '%s'. Must be boolean.",
+				u.Key,
+			)
+		}
+	}
+
+	exactKey := u.Key
+	exacts, exists := queries[exactKey]
+	if exists {
+		matching := []string{}
+		for _, exact := range exacts {
+			normal, valid := normaliseUUID(exact)
+			if !valid {
+				return filters.Exception(
+					exactKey,
+					"Invalid uuid match specified on attribute '%s'.",
+					u.Key,
+				)
+			}
+			matching = append(matching, normal)
+		}
+
+		valids = append(valids, UUIDExactFilter{
+			BaseFilter: &schema.BaseFilter{
+				QArgKey:    exactKey,
+				QArgValues: matching,
+			},
+			matching: matching,
+			column:   u.ColumnName,
+		})
+	}
+
+	return valids, nil
+}
@@ -0,0 +1,52 @@
+package attributes
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+type UUIDValue struct {
+	Value    *string
+	Provided bool
+}
+
+func (uv *UUIDValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(uv.Value)
+}
+func (uv *UUIDValue) UnmarshalJSON(data []byte) error {
+	uv.Provided = true
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	var val string
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	uv.Value = &val
+	return nil
+}
+func (uv UUIDValue) Equal(ouv UUIDValue) bool {
+	if uv.Value == nil {
+		return (ouv.Value == nil)
+	} else if ouv.Value == nil {
+		return false
+	}
+	return strings.EqualFold(*uv.Value, *ouv.Value)
+}
+
+func AssertUUID(val interface{}) UUIDValue {
+	uVal, ok := val.(UUIDValue)
+	if !ok {
+		plainVal, ok := val.(**string)
+		if !ok {
+			panic("Bad uuid value")
+		}
+		return UUIDValue{
+			Value:    *plainVal,
+			Provided: true,
+		}
+	}
+	return uVal
+}
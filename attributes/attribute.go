@@ -1,11 +1,31 @@
 package attributes
 
 import (
+	"encoding/gob"
 	"github.com/bor3ham/reja/schema"
 )
 
+// allows values to be held in a shared instance cache
+func init() {
+	gob.Register(BoolValue{})
+	gob.Register(DateValue{})
+	gob.Register(DatetimeValue{})
+	gob.Register(DecimalValue{})
+	gob.Register(EnumValue{})
+	gob.Register(IntegerArrayValue{})
+	gob.Register(IntegerValue{})
+	gob.Register(JSONValue{})
+	gob.Register(TextArrayValue{})
+	gob.Register(TextValue{})
+	gob.Register(UUIDValue{})
+}
+
 type AttributeStub struct{}
 
+func (stub AttributeStub) GetAccess() string {
+	return schema.ACCESS_READ_WRITE
+}
+
 func (stub AttributeStub) GetOrderMap() map[string]string {
 	return map[string]string{}
 }
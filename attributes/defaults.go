@@ -1,7 +1,9 @@
 package attributes
 
 import (
-// "github.com/bor3ham/reja/schema"
+	"crypto/rand"
+	"fmt"
+	// "github.com/bor3ham/reja/schema"
 )
 
 func DefaultText(textValue string) func(interface{}) TextValue {
@@ -21,3 +23,30 @@ func DefaultBool(boolValue bool) func(interface{}) BoolValue {
 		}
 	}
 }
+
+func DefaultEnum(choice string) func(interface{}) EnumValue {
+	return func(instance interface{}) EnumValue {
+		return EnumValue{
+			Provided: true,
+			Value:    &choice,
+		}
+	}
+}
+
+// generates a new random (version 4) uuid for each instance
+func DefaultNewUUID() func(interface{}) UUIDValue {
+	return func(instance interface{}) UUIDValue {
+		random := make([]byte, 16)
+		_, err := rand.Read(random)
+		if err != nil {
+			panic(err)
+		}
+		random[6] = (random[6] & 0x0f) | 0x40
+		random[8] = (random[8] & 0x3f) | 0x80
+		uuid := fmt.Sprintf("%x-%x-%x-%x-%x", random[0:4], random[4:6], random[6:8], random[8:10], random[10:])
+		return UUIDValue{
+			Provided: true,
+			Value:    &uuid,
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package authenticators
+
+import (
+	"crypto/subtle"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"net/http"
+)
+
+const API_KEY_HEADER = "X-API-Key"
+
+// static keys, each belonging to a user
+type APIKey struct {
+	// defaults to X-API-Key
+	Header string
+	Keys   map[string]schema.User
+}
+
+func (auth APIKey) GetUser(w http.ResponseWriter, r *http.Request, c schema.Context) (schema.User, error) {
+	header := auth.Header
+	if len(header) == 0 {
+		header = API_KEY_HEADER
+	}
+	provided := r.Header.Get(header)
+	if len(provided) == 0 {
+		return nil, nil
+	}
+
+	// compare against every key so timing does not reveal near matches
+	var user schema.User
+	for key, keyUser := range auth.Keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(provided)) == 1 {
+			user = keyUser
+		}
+	}
+	if user == nil {
+		return nil, utils.Unauthorised("Invalid API key.")
+	}
+	return user, nil
+}
@@ -0,0 +1,67 @@
+package authenticators
+
+import (
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"net/http"
+	"strings"
+)
+
+// authenticators return a nil user and no error when their credentials are not present
+// so that the next authenticator in a chain can be tried
+
+// tries each authenticator in order, using the first user found
+type Chain struct {
+	Authenticators []schema.Authenticator
+	// refuse requests without any credentials
+	Required bool
+}
+
+func (chain Chain) GetUser(w http.ResponseWriter, r *http.Request, c schema.Context) (schema.User, error) {
+	var firstErr error
+	for _, authenticator := range chain.Authenticators {
+		user, err := authenticator.GetUser(w, r, c)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if user != nil {
+			return user, nil
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if chain.Required {
+		return nil, utils.Unauthorised("Authentication credentials were not provided.")
+	}
+	return nil, nil
+}
+
+// the credentials of the authorization header, if it uses the given scheme
+func authorizationCredentials(r *http.Request, scheme string) (string, bool) {
+	header := r.Header.Get("Authorization")
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], scheme) {
+		return "", false
+	}
+	return strings.TrimSpace(parts[1]), true
+}
+
+// checks the result of a user lookup, an unknown user is unauthorised
+// errors other than authentication errors are unexpected
+func foundUser(user schema.User, err error) (schema.User, error) {
+	if err != nil {
+		_, ok := err.(utils.AuthError)
+		if ok {
+			return nil, err
+		}
+		panic(err)
+	}
+	if user == nil {
+		return nil, utils.Unauthorised("Unknown user.")
+	}
+	return user, nil
+}
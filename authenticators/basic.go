@@ -0,0 +1,60 @@
+package authenticators
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"net/http"
+)
+
+// http basic credentials checked against a users table
+type Basic struct {
+	Table          string
+	IDColumn       string
+	UsernameColumn string
+	PasswordColumn string
+	Realm          string
+
+	// compares the stored password hash with the provided password
+	CheckPassword func(hash string, password string) bool
+	// a hash of any password in the stored format, checked for unknown usernames
+	// so they take as long to reject as wrong passwords
+	DummyHash string
+	// loads the user with the matched id
+	LoadUser func(c schema.Context, id string) (schema.User, error)
+}
+
+func (auth Basic) GetUser(w http.ResponseWriter, r *http.Request, c schema.Context) (schema.User, error) {
+	username, password, provided := r.BasicAuth()
+	if !provided {
+		return nil, nil
+	}
+	if auth.CheckPassword == nil || auth.LoadUser == nil || len(auth.DummyHash) == 0 {
+		panic("Basic authenticator requires CheckPassword, LoadUser and DummyHash.")
+	}
+
+	var id, hash string
+	err := c.QueryRow(
+		fmt.Sprintf(
+			`select %s, %s from %s where %s = $1`,
+			auth.IDColumn,
+			auth.PasswordColumn,
+			auth.Table,
+			auth.UsernameColumn,
+		),
+		username,
+	).Scan(&id, &hash)
+	if err == sql.ErrNoRows {
+		// compared anyway so unknown usernames cannot be found by timing
+		auth.CheckPassword(auth.DummyHash, password)
+	}
+	if err == sql.ErrNoRows || (err == nil && !auth.CheckPassword(hash, password)) {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Basic realm="%s"`, auth.Realm))
+		return nil, utils.Unauthorised("Invalid username or password.")
+	}
+	if err != nil {
+		panic(err)
+	}
+	return foundUser(auth.LoadUser(c, id))
+}
@@ -0,0 +1,120 @@
+package authenticators
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const SESSION_COOKIE = "session"
+
+// a session value stored in a cookie, signed so it cannot be altered by the client
+type Cookie struct {
+	// defaults to session
+	Name   string
+	Secret []byte
+	// how long a signed value is accepted, forever if zero
+	MaxAge time.Duration
+	// also sends the cookie over plain http, only for local development
+	Insecure bool
+
+	// loads the user for the session value
+	LoadUser func(c schema.Context, value string) (schema.User, error)
+}
+
+func (auth Cookie) name() string {
+	if len(auth.Name) == 0 {
+		return SESSION_COOKIE
+	}
+	return auth.Name
+}
+
+func (auth Cookie) signature(payload string) string {
+	if len(auth.Secret) == 0 {
+		panic("Cookie authenticator requires a Secret.")
+	}
+	mac := hmac.New(sha256.New, auth.Secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signs a session value with the time it was issued
+func (auth Cookie) Sign(value string) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(value)) + "." + strconv.FormatInt(time.Now().Unix(), 10)
+	return payload + "." + auth.signature(payload)
+}
+
+// returns the session value of a signed cookie value
+func (auth Cookie) Verify(signed string) (string, error) {
+	invalid := utils.Unauthorised("Invalid session.")
+
+	parts := strings.Split(signed, ".")
+	if len(parts) != 3 {
+		return "", invalid
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(auth.signature(payload)), []byte(parts[2])) {
+		return "", invalid
+	}
+	issued, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", invalid
+	}
+	if auth.MaxAge > 0 && time.Since(time.Unix(issued, 0)) > auth.MaxAge {
+		return "", utils.Unauthorised("Session has expired.")
+	}
+	value, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", invalid
+	}
+	return string(value), nil
+}
+
+// starts a session by setting the signed cookie
+func (auth Cookie) SetCookie(w http.ResponseWriter, value string) {
+	cookie := http.Cookie{
+		Name:     auth.name(),
+		Value:    auth.Sign(value),
+		Path:     "/",
+		Secure:   !auth.Insecure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if auth.MaxAge > 0 {
+		cookie.MaxAge = int(auth.MaxAge.Seconds())
+	}
+	http.SetCookie(w, &cookie)
+}
+
+// ends a session by expiring the cookie
+func (auth Cookie) ClearCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     auth.name(),
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   !auth.Insecure,
+		HttpOnly: true,
+	})
+}
+
+func (auth Cookie) GetUser(w http.ResponseWriter, r *http.Request, c schema.Context) (schema.User, error) {
+	cookie, err := r.Cookie(auth.name())
+	if err != nil {
+		return nil, nil
+	}
+	if auth.LoadUser == nil {
+		panic("Cookie authenticator requires LoadUser.")
+	}
+	value, err := auth.Verify(cookie.Value)
+	if err != nil {
+		return nil, err
+	}
+	return foundUser(auth.LoadUser(c, value))
+}
@@ -0,0 +1,148 @@
+package authenticators
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const JWT_HS256 = "HS256"
+const JWT_RS256 = "RS256"
+
+// bearer tokens verified locally, signed with a shared secret (HS256) or a private key (RS256)
+type JWT struct {
+	// set one of these to choose the accepted algorithm
+	Secret    []byte
+	PublicKey *rsa.PublicKey
+
+	// checked when set
+	Issuer   string
+	Audience string
+	// allowed clock difference when checking expiry
+	Leeway time.Duration
+	// accepts tokens without an expiry claim, which are otherwise rejected
+	AllowNoExpiry bool
+
+	// loads the user described by the verified claims
+	LoadUser func(c schema.Context, claims map[string]interface{}) (schema.User, error)
+}
+
+func (auth JWT) GetUser(w http.ResponseWriter, r *http.Request, c schema.Context) (schema.User, error) {
+	token, provided := authorizationCredentials(r, "Bearer")
+	if !provided {
+		return nil, nil
+	}
+	if auth.LoadUser == nil {
+		panic("JWT authenticator requires LoadUser.")
+	}
+	claims, err := auth.verify(token)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		return nil, err
+	}
+	return foundUser(auth.LoadUser(c, claims))
+}
+
+func (auth JWT) algorithm() string {
+	if auth.PublicKey != nil {
+		return JWT_RS256
+	}
+	return JWT_HS256
+}
+
+// checks the signature and registered claims of a token, returning its claims
+func (auth JWT) verify(token string) (map[string]interface{}, error) {
+	invalid := utils.Unauthorised("Invalid token.")
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, invalid
+	}
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+	err := decodeSegment(parts[0], &header)
+	if err != nil {
+		return nil, invalid
+	}
+	// only the configured algorithm is accepted, the token cannot choose
+	if header.Algorithm != auth.algorithm() {
+		return nil, invalid
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalid
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	if auth.algorithm() == JWT_RS256 {
+		hash := sha256.Sum256(signed)
+		if rsa.VerifyPKCS1v15(auth.PublicKey, crypto.SHA256, hash[:], signature) != nil {
+			return nil, invalid
+		}
+	} else {
+		if len(auth.Secret) == 0 {
+			panic("JWT authenticator requires a Secret or PublicKey.")
+		}
+		mac := hmac.New(sha256.New, auth.Secret)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return nil, invalid
+		}
+	}
+
+	claims := map[string]interface{}{}
+	err = decodeSegment(parts[1], &claims)
+	if err != nil {
+		return nil, invalid
+	}
+	now := time.Now()
+	expiry, exists := claims["exp"].(float64)
+	if !exists && !auth.AllowNoExpiry {
+		return nil, invalid
+	}
+	if exists && now.Add(-auth.Leeway).After(time.Unix(int64(expiry), 0)) {
+		return nil, utils.Unauthorised("Token has expired.")
+	}
+	notBefore, exists := claims["nbf"].(float64)
+	if exists && now.Add(auth.Leeway).Before(time.Unix(int64(notBefore), 0)) {
+		return nil, utils.Unauthorised("Token is not yet valid.")
+	}
+	if len(auth.Issuer) > 0 && claims["iss"] != auth.Issuer {
+		return nil, invalid
+	}
+	if len(auth.Audience) > 0 && !audienceContains(claims["aud"], auth.Audience) {
+		return nil, invalid
+	}
+	return claims, nil
+}
+
+func decodeSegment(segment string, value interface{}) error {
+	blob, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(blob, value)
+}
+
+// the audience claim may be a single string or a list
+func audienceContains(claim interface{}, audience string) bool {
+	switch typed := claim.(type) {
+	case string:
+		return typed == audience
+	case []interface{}:
+		for _, item := range typed {
+			if item == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
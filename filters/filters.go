@@ -1,9 +1,9 @@
 package filters
 
 import (
-	"errors"
 	"fmt"
 	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
 )
 
 const ISNULL_SUFFIX = "__is_null"
@@ -12,6 +12,8 @@ const GT_SUFFIX = "__gt"
 const LT_SUFFIX = "__lt"
 const CONTAINS_SUFFIX = "__contains"
 const EXCLUDES_SUFFIX = "__excludes"
+const OVERLAPS_SUFFIX = "__overlaps"
+const PATH_SUFFIX = "__path"
 const AFTER_SUFFIX = "__after"
 const BEFORE_SUFFIX = "__before"
 const TYPE_SUFFIX = "__type"
@@ -24,6 +26,10 @@ type FilterDescription struct {
 	Examples    []string `json:"examples"`
 }
 
-func Exception(text string, args ...interface{}) ([]schema.Filter, error) {
-	return []schema.Filter{}, errors.New(fmt.Sprintf(text, args...))
+func Exception(parameter string, text string, args ...interface{}) ([]schema.Filter, error) {
+	return []schema.Filter{}, utils.ParameterError(
+		parameter,
+		utils.CODE_INVALID_FILTER,
+		fmt.Sprintf(text, args...),
+	)
 }
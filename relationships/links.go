@@ -17,3 +17,16 @@ func relationLink(c schema.Context, modelType string, id string, key string) str
 	)
 	return url
 }
+
+func relatedResourceLink(c schema.Context, modelType string, id string, key string) string {
+	server := c.GetServer()
+	request := c.GetRequest()
+	url := fmt.Sprintf(
+		"https://%s%s/%s/%s",
+		request.Host,
+		server.GetRoute(modelType),
+		id,
+		key,
+	)
+	return url
+}
@@ -1,14 +1,15 @@
 package relationships
 
 import (
-	"errors"
 	"fmt"
 	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
 )
 
 type ForeignKey struct {
 	RelationshipStub
 	Key        string
+	Access     string
 	ColumnName string
 	Type       string
 	Nullable   bool
@@ -18,10 +19,32 @@ type ForeignKey struct {
 func (fk ForeignKey) GetKey() string {
 	return fk.Key
 }
+func (fk ForeignKey) GetAccess() string {
+	return fk.Access
+}
 func (fk ForeignKey) GetType() string {
 	return fk.Type
 }
 
+func (fk ForeignKey) GetOrderType() string {
+	return fk.Type
+}
+func (fk ForeignKey) GetOrderCondition(modelTable string, relatedID string) string {
+	return fmt.Sprintf("%s = %s.%s", relatedID, modelTable, fk.ColumnName)
+}
+
+func (fk ForeignKey) GetFilterType() string {
+	return fk.GetOrderType()
+}
+func (fk ForeignKey) GetFilterCondition(
+	modelTable string,
+	idColumn string,
+	relatedTable string,
+	relatedIDColumn string,
+) string {
+	return fk.GetOrderCondition(modelTable, relatedTable+"."+relatedIDColumn)
+}
+
 func (fk ForeignKey) GetSelectExtra() ([]string, []interface{}) {
 	var destination *string
 	return []string{fk.ColumnName}, []interface{}{
@@ -78,9 +101,11 @@ func (fk ForeignKey) GetValues(
 		}
 
 		selfLink := relationLink(c, m.Type, myId, fk.Key)
+		relatedLink := relatedResourceLink(c, m.Type, myId, fk.Key)
 		newValue := schema.Result{
 			Links: map[string]*string{
-				"self": &selfLink,
+				"self":    &selfLink,
+				"related": &relatedLink,
 			},
 		}
 		if *stringId != nil {
@@ -140,7 +165,7 @@ func (fk *ForeignKey) Validate(c schema.Context, val interface{}) (interface{},
 
 	if fkVal.Data == nil {
 		if !fk.Nullable {
-			return nil, errors.New(fmt.Sprintf(
+			return nil, utils.RelationshipError(fk.Key, utils.CODE_NULL, fmt.Sprintf(
 				"Relationship '%s' invalid: Cannot be null.",
 				fk.Key,
 			))
@@ -150,7 +175,7 @@ func (fk *ForeignKey) Validate(c schema.Context, val interface{}) (interface{},
 
 	valType := fkVal.Data.Type
 	if fkVal.Data.ID == nil {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, utils.RelationshipError(fk.Key, utils.CODE_INVALID, fmt.Sprintf(
 			"Relationship '%s' invalid: Missing ID.",
 			fk.Key,
 		))
@@ -159,7 +184,7 @@ func (fk *ForeignKey) Validate(c schema.Context, val interface{}) (interface{},
 
 	// validate the type is correct
 	if valType != fk.Type {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, utils.RelationshipError(fk.Key, utils.CODE_INCORRECT_TYPE, fmt.Sprintf(
 			"Relationship '%s' invalid: Incorrect type.",
 			fk.Key,
 		))
@@ -175,7 +200,7 @@ func (fk *ForeignKey) Validate(c schema.Context, val interface{}) (interface{},
 		panic(err)
 	}
 	if len(instances) == 0 {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, utils.RelationshipError(fk.Key, utils.CODE_NOT_FOUND, fmt.Sprintf(
 			"Relationship '%s' invalid: %s ID '%s' does not exist.",
 			fk.Key,
 			fk.Type,
@@ -185,7 +210,7 @@ func (fk *ForeignKey) Validate(c schema.Context, val interface{}) (interface{},
 	// check that the user has access to the object
 	canAccess := c.CanAccessAllInstances(instances)
 	if !canAccess {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, utils.RelationshipError(fk.Key, utils.CODE_FORBIDDEN, fmt.Sprintf(
 			"Relationship '%s' invalid: You do not have access to %s ID '%s'.",
 			fk.Key,
 			fk.Type,
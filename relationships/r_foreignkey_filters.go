@@ -92,6 +92,7 @@ func (fk ForeignKey) ValidateFilters(queries map[string][]string) ([]schema.Filt
 	if exists {
 		if len(nullStrings) != 1 {
 			return filters.Exception(
+				nullKey,
 				"Cannot null check attribute '%s' against more than one value.",
 				fk.Key,
 			)
@@ -120,6 +121,7 @@ func (fk ForeignKey) ValidateFilters(queries map[string][]string) ([]schema.Filt
 			})
 		} else {
 			return filters.Exception(
+				nullKey,
 				"Invalid null check value on attribute '%s'. Must be boolean.",
 				fk.Key,
 			)
@@ -131,6 +133,7 @@ func (fk ForeignKey) ValidateFilters(queries map[string][]string) ([]schema.Filt
 	if exists {
 		if nullsOnly {
 			return filters.Exception(
+				exactKey,
 				"Cannot match attribute '%s' to an exact value and null.",
 				fk.Key,
 			)
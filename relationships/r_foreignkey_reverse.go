@@ -1,17 +1,18 @@
 package relationships
 
 import (
-	"errors"
-	"math"
 	"fmt"
+	"github.com/bor3ham/reja/filters"
 	"github.com/bor3ham/reja/schema"
 	"github.com/bor3ham/reja/utils"
+	"math"
 	"strings"
 )
 
 type ForeignKeyReverse struct {
 	RelationshipStub
 	Key            string
+	Access         string
 	SourceTable    string
 	SourceIDColumn string
 	ColumnName     string
@@ -23,10 +24,59 @@ type ForeignKeyReverse struct {
 func (fkr ForeignKeyReverse) GetKey() string {
 	return fkr.Key
 }
+func (fkr ForeignKeyReverse) GetAccess() string {
+	return fkr.Access
+}
 func (fkr ForeignKeyReverse) GetType() string {
 	return fkr.Type
 }
 
+// orders by the number of related items, counting the same rows as the count filters
+func (fkr ForeignKeyReverse) GetOrderMap(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) map[string]schema.Query {
+	condition, args := countedCondition(c, fkr.Type, fkr.SourceIDColumn, nextArg)
+	orders := map[string]schema.Query{}
+	orders[fkr.Key+filters.COUNT_SUFFIX] = schema.Query{
+		Query: fmt.Sprintf(
+			"(select count(*) from %s as counted where counted.%s = %s.%s%s)",
+			fkr.SourceTable,
+			fkr.ColumnName,
+			modelTable,
+			idColumn,
+			condition,
+		),
+		Args: args,
+	}
+	return orders
+}
+
+func (fkr ForeignKeyReverse) GetFilterType() string {
+	return fkr.Type
+}
+
+// the source table is aliased so it may be the owning table
+func (fkr ForeignKeyReverse) GetFilterCondition(
+	modelTable string,
+	idColumn string,
+	relatedTable string,
+	relatedIDColumn string,
+) string {
+	return fmt.Sprintf(
+		"%s.%s in (select linked.%s from %s as linked where linked.%s = %s.%s)",
+		relatedTable,
+		relatedIDColumn,
+		fkr.SourceIDColumn,
+		fkr.SourceTable,
+		fkr.ColumnName,
+		modelTable,
+		idColumn,
+	)
+}
+
 func (fkr ForeignKeyReverse) GetDefaultValue() interface{} {
 	return schema.Page{}
 }
@@ -54,25 +104,35 @@ func (fkr ForeignKeyReverse) GetValues(
 
 	server := c.GetServer()
 	otherModel := server.GetModel(fkr.Type)
-	order, _, err := otherModel.GetOrderQuery(otherModel.DefaultOrder)
+	order, orderArgs, _, err := otherModel.GetOrderQuery(c, otherModel.DefaultOrder, len(ids)+1)
 	if err != nil {
 		panic(err)
 	}
+	args = append(args, orderArgs...)
 
 	query := fmt.Sprintf(
 		`
-			select
-				%s,
-				%s
-			from %s
+			select other_id, own_id, total, position
+			from (
+				select
+					%s as other_id,
+					%s as own_id,
+					count(*) over (partition by %s) as total,
+					row_number() over (partition by %s %s) as position
+				from %s
+				where %s
+			) as numbered
 			where %s
-			%s
+			order by own_id, position
 		`,
 		fkr.SourceIDColumn,
 		fkr.ColumnName,
+		fkr.ColumnName,
+		fkr.ColumnName,
+		order,
 		fkr.SourceTable,
 		filter,
-		order,
+		pageCondition("position", offset, pageSize),
 	)
 	rows, err := c.Query(query, args...)
 	if err != nil {
@@ -94,29 +154,26 @@ func (fkr ForeignKeyReverse) GetValues(
 	// go through result data
 	for rows.Next() {
 		var otherId, ownId string
-		rows.Scan(&otherId, &ownId)
+		var total, position int
+		err := rows.Scan(&otherId, &ownId, &total, &position)
+		if err != nil {
+			panic(err)
+		}
 		value, exists := values[ownId]
 		if !exists {
 			panic("Found unexpected id in results")
 		}
-		total, ok := value.Metadata["total"].(int)
-		if !ok {
-			panic("Bad total received")
-		}
-		count, ok := value.Metadata["count"].(int)
-		if !ok {
-			panic("Bad count received")
-		}
-		if total >= offset && (pageSize < 0 || count < pageSize) {
-			count += 1
-			value.Data = append(value.Data, schema.InstancePointer{
-				ID:   &otherId,
-				Type: fkr.Type,
-			})
-			value.Metadata["count"] = count
-		}
-		total += 1
+		// counted across every page, as total
+		value.Metadata["count"] = total
 		value.Metadata["total"] = total
+		if !inPage(position, offset, pageSize) {
+			values[ownId] = value
+			continue
+		}
+		value.Data = append(value.Data, schema.InstancePointer{
+			ID:   &otherId,
+			Type: fkr.Type,
+		})
 
 		// add to maps
 		_, exists = maps[ownId]
@@ -128,6 +185,10 @@ func (fkr ForeignKeyReverse) GetValues(
 		// update value
 		values[ownId] = value
 	}
+	err = rows.Err()
+	if err != nil {
+		panic(err)
+	}
 	// create the links
 	for id, value := range values {
 		total, ok := value.Metadata["total"].(int)
@@ -142,6 +203,8 @@ func (fkr ForeignKeyReverse) GetValues(
 			total,
 			map[string][]string{},
 		)
+		relatedLink := relatedResourceLink(c, m.Type, id, fkr.Key)
+		value.Links["related"] = &relatedLink
 		values[id] = value
 	}
 	// generalise values
@@ -185,7 +248,7 @@ func (fkr *ForeignKeyReverse) Validate(c schema.Context, val interface{}) (inter
 	// validate the types are correct
 	for _, pointer := range fkrVal.Data {
 		if pointer.Type != fkr.Type {
-			return nil, errors.New(fmt.Sprintf(
+			return nil, utils.RelationshipError(fkr.Key, utils.CODE_INCORRECT_TYPE, fmt.Sprintf(
 				"Relationship '%s' invalid: Incorrect type in set.",
 				fkr.Key,
 			))
@@ -196,7 +259,7 @@ func (fkr *ForeignKeyReverse) Validate(c schema.Context, val interface{}) (inter
 	for _, pointer := range fkrVal.Data {
 		_, exists := ids[*pointer.ID]
 		if exists {
-			return nil, errors.New(fmt.Sprintf(
+			return nil, utils.RelationshipError(fkr.Key, utils.CODE_DUPLICATE, fmt.Sprintf(
 				"Relationship '%s' invalid: Duplicate object in set.",
 				fkr.Key,
 			))
@@ -219,7 +282,7 @@ func (fkr *ForeignKeyReverse) Validate(c schema.Context, val interface{}) (inter
 		panic(err)
 	}
 	if len(instances) < len(ids) {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, utils.RelationshipError(fkr.Key, utils.CODE_NOT_FOUND, fmt.Sprintf(
 			"Relationship '%s' invalid: Not all objects in set exist",
 			fkr.Key,
 		))
@@ -227,7 +290,7 @@ func (fkr *ForeignKeyReverse) Validate(c schema.Context, val interface{}) (inter
 	// check that the user has access to the objects
 	canAccess := c.CanAccessAllInstances(instances)
 	if !canAccess {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, utils.RelationshipError(fkr.Key, utils.CODE_FORBIDDEN, fmt.Sprintf(
 			"Relationship '%s' invalid: You do not have access to all objects in set.",
 			fkr.Key,
 		))
@@ -272,7 +335,7 @@ func (fkr *ForeignKeyReverse) ValidateUpdate(
 		for key, _ := range oldCounts {
 			_, exists := newCounts[key]
 			if !exists {
-				return nil, errors.New(fmt.Sprintf(
+				return nil, utils.RelationshipError(fkr.Key, utils.CODE_INVALID, fmt.Sprintf(
 					"Relationship '%s' invalid: Cannot remove item from non nullable reverse relation.",
 					fkr.Key,
 				))
@@ -385,6 +448,26 @@ func (fkr *ForeignKeyReverse) GetUpdateQueries(id string, oldVal interface{}, ne
 	return queries
 }
 
+func (fkr *ForeignKeyReverse) GetDeleteQueries(id string) []schema.Query {
+	// non nullable reverse relations are left to the database constraint
+	if !fkr.Nullable {
+		return []schema.Query{}
+	}
+	return []schema.Query{
+		schema.Query{
+			Query: fmt.Sprintf(
+				"update %s set %s = null where %s = $1",
+				fkr.SourceTable,
+				fkr.ColumnName,
+				fkr.ColumnName,
+			),
+			Args: []interface{}{
+				id,
+			},
+		},
+	}
+}
+
 func AssertForeignKeyReverse(val interface{}) schema.Page {
 	fkrVal, ok := val.(schema.Page)
 	if !ok {
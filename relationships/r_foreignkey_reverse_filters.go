@@ -54,6 +54,10 @@ func (f ForeignKeyReverseContainsFilter) GetWhere(
 		}
 		ids = append(ids, id)
 	}
+	err = rows.Err()
+	if err != nil {
+		panic(err)
+	}
 
 	if f.exclude {
 		if len(ids) > 0 {
@@ -150,6 +154,10 @@ func (f ForeignKeyReverseCountFilter) GetWhere(
 		}
 		ids = append(ids, id)
 	}
+	err = rows.Err()
+	if err != nil {
+		panic(err)
+	}
 
 	if len(ids) > 0 {
 		spots := []string{}
@@ -257,6 +265,7 @@ func (fkr ForeignKeyReverse) ValidateFilters(queries map[string][]string) ([]sch
 	if exists {
 		if len(exactCountStrings) != 1 {
 			return filters.Exception(
+				exactCountKey,
 				"Cannot compare count of relationship '%s' to more than one value.",
 				fkr.Key,
 			)
@@ -266,6 +275,7 @@ func (fkr ForeignKeyReverse) ValidateFilters(queries map[string][]string) ([]sch
 		intValue, err := strconv.Atoi(stringValue)
 		if err != nil {
 			return filters.Exception(
+				exactCountKey,
 				"Invalid count comparison value on relationship '%s'. Must be integer.",
 				fkr.Key,
 			)
@@ -288,6 +298,7 @@ func (fkr ForeignKeyReverse) ValidateFilters(queries map[string][]string) ([]sch
 	if exists {
 		if len(lesserCountStrings) != 1 {
 			return filters.Exception(
+				lesserCountKey,
 				"Cannot compare count of relationship '%s' to more than one value.",
 				fkr.Key,
 			)
@@ -297,6 +308,7 @@ func (fkr ForeignKeyReverse) ValidateFilters(queries map[string][]string) ([]sch
 		intValue, err := strconv.Atoi(stringValue)
 		if err != nil {
 			return filters.Exception(
+				lesserCountKey,
 				"Invalid count comparison value on relationship '%s'. Must be integer.",
 				fkr.Key,
 			)
@@ -319,6 +331,7 @@ func (fkr ForeignKeyReverse) ValidateFilters(queries map[string][]string) ([]sch
 	if exists {
 		if len(greaterCountStrings) != 1 {
 			return filters.Exception(
+				greaterCountKey,
 				"Cannot compare count of relationship '%s' to more than one value.",
 				fkr.Key,
 			)
@@ -328,6 +341,7 @@ func (fkr ForeignKeyReverse) ValidateFilters(queries map[string][]string) ([]sch
 		intValue, err := strconv.Atoi(stringValue)
 		if err != nil {
 			return filters.Exception(
+				greaterCountKey,
 				"Invalid count comparison value on relationship '%s'. Must be integer.",
 				fkr.Key,
 			)
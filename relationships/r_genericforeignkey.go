@@ -1,14 +1,16 @@
 package relationships
 
 import (
-	"errors"
 	"fmt"
 	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"github.com/lib/pq"
 )
 
 type GenericForeignKey struct {
 	RelationshipStub
 	Key            string
+	Access         string
 	TypeColumnName string
 	IDColumnName   string
 	Nullable       bool
@@ -19,10 +21,45 @@ type GenericForeignKey struct {
 func (gfk GenericForeignKey) GetKey() string {
 	return gfk.Key
 }
+func (gfk GenericForeignKey) GetAccess() string {
+	return gfk.Access
+}
 func (gfk GenericForeignKey) GetType() string {
 	return ""
 }
 
+// only relationships limited to a single type can be ordered through
+func (gfk GenericForeignKey) GetOrderType() string {
+	if len(gfk.ValidTypes) != 1 {
+		return ""
+	}
+	return gfk.ValidTypes[0]
+}
+func (gfk GenericForeignKey) GetOrderCondition(modelTable string, relatedID string) string {
+	// generic id columns may not share the type of the related id column
+	return fmt.Sprintf(
+		"%s::text = %s.%s::text and %s.%s = %s",
+		relatedID,
+		modelTable,
+		gfk.IDColumnName,
+		modelTable,
+		gfk.TypeColumnName,
+		pq.QuoteLiteral(gfk.GetOrderType()),
+	)
+}
+
+func (gfk GenericForeignKey) GetFilterType() string {
+	return gfk.GetOrderType()
+}
+func (gfk GenericForeignKey) GetFilterCondition(
+	modelTable string,
+	idColumn string,
+	relatedTable string,
+	relatedIDColumn string,
+) string {
+	return gfk.GetOrderCondition(modelTable, relatedTable+"."+relatedIDColumn)
+}
+
 func (gfk GenericForeignKey) GetSelectExtra() ([]string, []interface{}) {
 	var typeDest *string
 	var idDest *string
@@ -88,9 +125,11 @@ func (gfk GenericForeignKey) GetValues(
 		}
 
 		selfLink := relationLink(c, m.Type, myId, gfk.Key)
+		relatedLink := relatedResourceLink(c, m.Type, myId, gfk.Key)
 		newValue := schema.Result{
 			Links: map[string]*string{
-				"self": &selfLink,
+				"self":    &selfLink,
+				"related": &relatedLink,
 			},
 		}
 		if *stringId != nil {
@@ -151,7 +190,7 @@ func (gfk *GenericForeignKey) Validate(c schema.Context, val interface{}) (inter
 
 	if gfkVal.Data == nil {
 		if !gfk.Nullable {
-			return nil, errors.New(fmt.Sprintf(
+			return nil, utils.RelationshipError(gfk.Key, utils.CODE_NULL, fmt.Sprintf(
 				"Relationship '%s' invalid: Cannot be null.",
 				gfk.Key,
 			))
@@ -161,7 +200,7 @@ func (gfk *GenericForeignKey) Validate(c schema.Context, val interface{}) (inter
 
 	valType := gfkVal.Data.Type
 	if gfkVal.Data.ID == nil {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, utils.RelationshipError(gfk.Key, utils.CODE_INVALID, fmt.Sprintf(
 			"Relationship '%s' invalid: Missing ID.",
 			gfk.Key,
 		))
@@ -177,7 +216,7 @@ func (gfk *GenericForeignKey) Validate(c schema.Context, val interface{}) (inter
 			}
 		}
 		if !valid {
-			return nil, errors.New(fmt.Sprintf(
+			return nil, utils.RelationshipError(gfk.Key, utils.CODE_INCORRECT_TYPE, fmt.Sprintf(
 				"Relationship '%s' invalid: Bad type for relation.",
 				gfk.Key,
 			))
@@ -187,7 +226,7 @@ func (gfk *GenericForeignKey) Validate(c schema.Context, val interface{}) (inter
 	model := c.GetServer().GetModel(valType)
 	// validate the type exists
 	if model == nil {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, utils.RelationshipError(gfk.Key, utils.CODE_INCORRECT_TYPE, fmt.Sprintf(
 			"Relationship '%s' invalid: Non existent type.",
 			gfk.Key,
 		))
@@ -200,7 +239,7 @@ func (gfk *GenericForeignKey) Validate(c schema.Context, val interface{}) (inter
 		panic(err)
 	}
 	if len(instances) == 0 {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, utils.RelationshipError(gfk.Key, utils.CODE_NOT_FOUND, fmt.Sprintf(
 			"Relationship '%s' invalid: %s ID '%s' does not exist.",
 			gfk.Key,
 			valType,
@@ -210,7 +249,7 @@ func (gfk *GenericForeignKey) Validate(c schema.Context, val interface{}) (inter
 	// check that the user has access to the object
 	canAccess := c.CanAccessAllInstances(instances)
 	if !canAccess {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, utils.RelationshipError(gfk.Key, utils.CODE_FORBIDDEN, fmt.Sprintf(
 			"Relationship '%s' invalid: You do not have access to %s ID '%s'.",
 			gfk.Key,
 			valType,
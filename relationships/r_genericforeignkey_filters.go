@@ -209,6 +209,7 @@ func (gfk GenericForeignKey) ValidateFilters(queries map[string][]string) ([]sch
 	if exists {
 		if len(nullStrings) != 1 {
 			return filters.Exception(
+				nullKey,
 				"Cannot null check attribute '%s' against more than one value.",
 				gfk.Key,
 			)
@@ -237,6 +238,7 @@ func (gfk GenericForeignKey) ValidateFilters(queries map[string][]string) ([]sch
 			})
 		} else {
 			return filters.Exception(
+				nullKey,
 				"Invalid null check value on attribute '%s'. Must be boolean.",
 				gfk.Key,
 			)
@@ -248,6 +250,7 @@ func (gfk GenericForeignKey) ValidateFilters(queries map[string][]string) ([]sch
 	if exists {
 		if nullsOnly {
 			return filters.Exception(
+				typeKey,
 				"Cannot match attribute '%s' to a type value and null.",
 				gfk.Key,
 			)
@@ -265,6 +268,7 @@ func (gfk GenericForeignKey) ValidateFilters(queries map[string][]string) ([]sch
 				}
 				if !valid {
 					return filters.Exception(
+						typeKey,
 						"Cannot match attribute '%s' type to invalid value.",
 						gfk.Key,
 					)
@@ -288,6 +292,7 @@ func (gfk GenericForeignKey) ValidateFilters(queries map[string][]string) ([]sch
 	if exists {
 		if nullsOnly {
 			return filters.Exception(
+				idKey,
 				"Cannot match attribute '%s' to an ID value and null.",
 				gfk.Key,
 			)
@@ -313,6 +318,7 @@ func (gfk GenericForeignKey) ValidateFilters(queries map[string][]string) ([]sch
 	if exists {
 		if nullsOnly {
 			return filters.Exception(
+				exactKey,
 				"Cannot match attribute '%s' to an exact value and null.",
 				gfk.Key,
 			)
@@ -324,6 +330,7 @@ func (gfk GenericForeignKey) ValidateFilters(queries map[string][]string) ([]sch
 			splitValue := strings.Split(cleanValue, ":")
 			if len(splitValue) != 2 {
 				return filters.Exception(
+					exactKey,
 					"Invalid exact match on attribute '%s'. Must be instance pointer in format Type:ID.",
 					gfk.Key,
 				)
@@ -338,6 +345,7 @@ func (gfk GenericForeignKey) ValidateFilters(queries map[string][]string) ([]sch
 				}
 				if !valid {
 					return filters.Exception(
+						exactKey,
 						"Invalid exact match on attribute '%s'. Must be valid type choice for relationship.",
 						gfk.Key,
 					)
@@ -1,17 +1,19 @@
 package relationships
 
 import (
-	"errors"
-	"math"
 	"fmt"
+	"github.com/bor3ham/reja/filters"
 	"github.com/bor3ham/reja/schema"
 	"github.com/bor3ham/reja/utils"
+	"github.com/lib/pq"
+	"math"
 	"strings"
 )
 
 type GenericForeignKeyReverse struct {
 	RelationshipStub
 	Key           string
+	Access        string
 	Table         string
 	OwnType       string
 	OwnTypeColumn string
@@ -25,10 +27,61 @@ type GenericForeignKeyReverse struct {
 func (gfkr GenericForeignKeyReverse) GetKey() string {
 	return gfkr.Key
 }
+func (gfkr GenericForeignKeyReverse) GetAccess() string {
+	return gfkr.Access
+}
 func (gfkr GenericForeignKeyReverse) GetType() string {
 	return gfkr.OtherType
 }
 
+// orders by the number of related items, counting the same rows as the count filters
+func (gfkr GenericForeignKeyReverse) GetOrderMap(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) map[string]schema.Query {
+	condition, args := countedCondition(c, gfkr.OtherType, gfkr.OtherIDColumn, nextArg)
+	orders := map[string]schema.Query{}
+	orders[gfkr.Key+filters.COUNT_SUFFIX] = schema.Query{
+		Query: fmt.Sprintf(
+			"(select count(*) from %s as counted where counted.%s = %s and counted.%s = %s.%s%s)",
+			gfkr.Table,
+			gfkr.OwnTypeColumn,
+			pq.QuoteLiteral(gfkr.OwnType),
+			gfkr.OwnIDColumn,
+			modelTable,
+			idColumn,
+			condition,
+		),
+		Args: args,
+	}
+	return orders
+}
+
+func (gfkr GenericForeignKeyReverse) GetFilterType() string {
+	return gfkr.OtherType
+}
+func (gfkr GenericForeignKeyReverse) GetFilterCondition(
+	modelTable string,
+	idColumn string,
+	relatedTable string,
+	relatedIDColumn string,
+) string {
+	return fmt.Sprintf(
+		"%s.%s in (select linked.%s from %s as linked where linked.%s = %s and linked.%s::text = %s.%s::text)",
+		relatedTable,
+		relatedIDColumn,
+		gfkr.OtherIDColumn,
+		gfkr.Table,
+		gfkr.OwnTypeColumn,
+		pq.QuoteLiteral(gfkr.OwnType),
+		gfkr.OwnIDColumn,
+		modelTable,
+		idColumn,
+	)
+}
+
 func (gfkr GenericForeignKeyReverse) GetDefaultValue() interface{} {
 	return schema.Page{}
 }
@@ -49,7 +102,7 @@ func (gfkr GenericForeignKeyReverse) GetValues(
 
 	server := c.GetServer()
 	otherModel := server.GetModel(gfkr.OtherType)
-	order, _, err := otherModel.GetOrderQuery(otherModel.DefaultOrder)
+	order, orderArgs, _, err := otherModel.GetOrderQuery(c, otherModel.DefaultOrder, len(ids)+2)
 	if err != nil {
 		panic(err)
 	}
@@ -60,23 +113,33 @@ func (gfkr GenericForeignKeyReverse) GetValues(
 		spots = append(spots, fmt.Sprintf("$%d", index+2))
 		args = append(args, id)
 	}
+	args = append(args, orderArgs...)
 	idFilter := fmt.Sprintf("%s in (%s)", gfkr.OwnIDColumn, strings.Join(spots, ", "))
 	typeFilter := fmt.Sprintf("%s = $1", gfkr.OwnTypeColumn)
 	query := fmt.Sprintf(
 		`
-			select
-				%s,
-				%s
-			from %s
-			where (%s and %s)
-			%s
+			select other_id, own_id, total, position
+			from (
+				select
+					%s as other_id,
+					%s as own_id,
+					count(*) over (partition by %s) as total,
+					row_number() over (partition by %s %s) as position
+				from %s
+				where (%s and %s)
+			) as numbered
+			where %s
+			order by own_id, position
 	    `,
 		gfkr.OtherIDColumn,
 		gfkr.OwnIDColumn,
+		gfkr.OwnIDColumn,
+		gfkr.OwnIDColumn,
+		order,
 		gfkr.Table,
 		idFilter,
 		typeFilter,
-		order,
+		pageCondition("position", offset, pageSize),
 	)
 	rows, err := c.Query(query, append([]interface{}{gfkr.OwnType}, args...)...)
 	if err != nil {
@@ -98,29 +161,26 @@ func (gfkr GenericForeignKeyReverse) GetValues(
 	// go through result data
 	for rows.Next() {
 		var otherId, ownId string
-		rows.Scan(&otherId, &ownId)
+		var total, position int
+		err := rows.Scan(&otherId, &ownId, &total, &position)
+		if err != nil {
+			panic(err)
+		}
 		value, exists := values[ownId]
 		if !exists {
 			panic("Found unexpected id in results")
 		}
-		total, ok := value.Metadata["total"].(int)
-		if !ok {
-			panic("Bad total received")
-		}
-		count, ok := value.Metadata["count"].(int)
-		if !ok {
-			panic("Bad count received")
-		}
-		if total >= offset && (pageSize < 0 || count < pageSize) {
-			count += 1
-			value.Data = append(value.Data, schema.InstancePointer{
-				ID:   &otherId,
-				Type: gfkr.OtherType,
-			})
-			value.Metadata["count"] = count
-		}
-		total += 1
+		// counted across every page, as total
+		value.Metadata["count"] = total
 		value.Metadata["total"] = total
+		if !inPage(position, offset, pageSize) {
+			values[ownId] = value
+			continue
+		}
+		value.Data = append(value.Data, schema.InstancePointer{
+			ID:   &otherId,
+			Type: gfkr.OtherType,
+		})
 		// update the value
 		values[ownId] = value
 
@@ -132,6 +192,10 @@ func (gfkr GenericForeignKeyReverse) GetValues(
 		}
 		maps[ownId][gfkr.OtherType] = append(maps[ownId][gfkr.OtherType], otherId)
 	}
+	err = rows.Err()
+	if err != nil {
+		panic(err)
+	}
 	// create the links
 	for id, value := range values {
 		total, ok := value.Metadata["total"].(int)
@@ -146,6 +210,8 @@ func (gfkr GenericForeignKeyReverse) GetValues(
 			total,
 			map[string][]string{},
 		)
+		relatedLink := relatedResourceLink(c, m.Type, id, gfkr.Key)
+		value.Links["related"] = &relatedLink
 		values[id] = value
 	}
 	// generalise values
@@ -195,7 +261,7 @@ func (gfkr *GenericForeignKeyReverse) Validate(
 	// validate the types are correct
 	for _, pointer := range gfkrVal.Data {
 		if pointer.Type != gfkr.OtherType {
-			return nil, errors.New(fmt.Sprintf(
+			return nil, utils.RelationshipError(gfkr.Key, utils.CODE_INCORRECT_TYPE, fmt.Sprintf(
 				"Relationship '%s' invalid: Incorrect type in set.",
 				gfkr.Key,
 			))
@@ -206,7 +272,7 @@ func (gfkr *GenericForeignKeyReverse) Validate(
 	for _, pointer := range gfkrVal.Data {
 		_, exists := ids[*pointer.ID]
 		if exists {
-			return nil, errors.New(fmt.Sprintf(
+			return nil, utils.RelationshipError(gfkr.Key, utils.CODE_DUPLICATE, fmt.Sprintf(
 				"Relationship '%s' invalid: Duplicate object in set.",
 				gfkr.Key,
 			))
@@ -229,7 +295,7 @@ func (gfkr *GenericForeignKeyReverse) Validate(
 		panic(err)
 	}
 	if len(instances) < len(ids) {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, utils.RelationshipError(gfkr.Key, utils.CODE_NOT_FOUND, fmt.Sprintf(
 			"Relationship '%s' invalid: Not all objects in set exist",
 			gfkr.Key,
 		))
@@ -237,7 +303,7 @@ func (gfkr *GenericForeignKeyReverse) Validate(
 	// check that the user has access to the objects
 	canAccess := c.CanAccessAllInstances(instances)
 	if !canAccess {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, utils.RelationshipError(gfkr.Key, utils.CODE_FORBIDDEN, fmt.Sprintf(
 			"Relationship '%s' invalid: You do not have access to all objects in set.",
 			gfkr.Key,
 		))
@@ -282,7 +348,7 @@ func (gfkr *GenericForeignKeyReverse) ValidateUpdate(
 		for key, _ := range oldCounts {
 			_, exists := newCounts[key]
 			if !exists {
-				return nil, errors.New(fmt.Sprintf(
+				return nil, utils.RelationshipError(gfkr.Key, utils.CODE_INVALID, fmt.Sprintf(
 					"Relationship '%s' invalid: Cannot remove item from non nullable reverse relation.",
 					gfkr.Key,
 				))
@@ -406,6 +472,42 @@ func (gfkr *GenericForeignKeyReverse) GetUpdateQueries(id string, oldVal interfa
 	return queries
 }
 
+func (gfkr *GenericForeignKeyReverse) GetDeleteQueries(id string) []schema.Query {
+	// generic relations have no database constraint to fall back on
+	if !gfkr.Nullable {
+		return []schema.Query{
+			schema.Query{
+				Query: fmt.Sprintf(
+					"delete from %s where %s = $1 and %s = $2",
+					gfkr.Table,
+					gfkr.OwnTypeColumn,
+					gfkr.OwnIDColumn,
+				),
+				Args: []interface{}{
+					gfkr.OwnType,
+					id,
+				},
+			},
+		}
+	}
+	return []schema.Query{
+		schema.Query{
+			Query: fmt.Sprintf(
+				"update %s set %s = null, %s = null where %s = $1 and %s = $2",
+				gfkr.Table,
+				gfkr.OwnTypeColumn,
+				gfkr.OwnIDColumn,
+				gfkr.OwnTypeColumn,
+				gfkr.OwnIDColumn,
+			),
+			Args: []interface{}{
+				gfkr.OwnType,
+				id,
+			},
+		},
+	}
+}
+
 func AssertGenericForeignKeyReverse(val interface{}) schema.Page {
 	gfkrVal, ok := val.(schema.Page)
 	if !ok {
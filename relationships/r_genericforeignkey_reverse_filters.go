@@ -60,6 +60,10 @@ func (f GenericForeignKeyReverseContainsFilter) GetWhere(
 		}
 		ids = append(ids, id)
 	}
+	err = rows.Err()
+	if err != nil {
+		panic(err)
+	}
 
 	if f.exclude {
 		if len(ids) > 0 {
@@ -163,6 +167,10 @@ func (f GenericForeignKeyReverseCountFilter) GetWhere(
 		}
 		ids = append(ids, id)
 	}
+	err = rows.Err()
+	if err != nil {
+		panic(err)
+	}
 
 	if len(ids) > 0 {
 		spots := []string{}
@@ -278,6 +286,7 @@ func (gfkr GenericForeignKeyReverse) ValidateFilters(queries map[string][]string
 	if exists {
 		if len(exactCountStrings) != 1 {
 			return filters.Exception(
+				exactCountKey,
 				"Cannot compare count of relationship '%s' to more than one value.",
 				gfkr.Key,
 			)
@@ -287,6 +296,7 @@ func (gfkr GenericForeignKeyReverse) ValidateFilters(queries map[string][]string
 		intValue, err := strconv.Atoi(stringValue)
 		if err != nil {
 			return filters.Exception(
+				exactCountKey,
 				"Invalid count comparison value on relationship '%s'. Must be integer.",
 				gfkr.Key,
 			)
@@ -312,6 +322,7 @@ func (gfkr GenericForeignKeyReverse) ValidateFilters(queries map[string][]string
 	if exists {
 		if len(lesserCountStrings) != 1 {
 			return filters.Exception(
+				lesserCountKey,
 				"Cannot compare count of relationship '%s' to more than one value.",
 				gfkr.Key,
 			)
@@ -321,6 +332,7 @@ func (gfkr GenericForeignKeyReverse) ValidateFilters(queries map[string][]string
 		intValue, err := strconv.Atoi(stringValue)
 		if err != nil {
 			return filters.Exception(
+				lesserCountKey,
 				"Invalid count comparison value on relationship '%s'. Must be integer.",
 				gfkr.Key,
 			)
@@ -346,6 +358,7 @@ func (gfkr GenericForeignKeyReverse) ValidateFilters(queries map[string][]string
 	if exists {
 		if len(greaterCountStrings) != 1 {
 			return filters.Exception(
+				greaterCountKey,
 				"Cannot compare count of relationship '%s' to more than one value.",
 				gfkr.Key,
 			)
@@ -355,6 +368,7 @@ func (gfkr GenericForeignKeyReverse) ValidateFilters(queries map[string][]string
 		intValue, err := strconv.Atoi(stringValue)
 		if err != nil {
 			return filters.Exception(
+				greaterCountKey,
 				"Invalid count comparison value on relationship '%s'. Must be integer.",
 				gfkr.Key,
 			)
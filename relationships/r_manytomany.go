@@ -1,17 +1,18 @@
 package relationships
 
 import (
-	"errors"
-	"math"
 	"fmt"
+	"github.com/bor3ham/reja/filters"
 	"github.com/bor3ham/reja/schema"
 	"github.com/bor3ham/reja/utils"
+	"math"
 	"strings"
 )
 
 type ManyToMany struct {
 	RelationshipStub
 	Key           string
+	Access        string
 	Table         string
 	OwnIDColumn   string
 	OtherIDColumn string
@@ -22,10 +23,57 @@ type ManyToMany struct {
 func (m2m ManyToMany) GetKey() string {
 	return m2m.Key
 }
+func (m2m ManyToMany) GetAccess() string {
+	return m2m.Access
+}
 func (m2m ManyToMany) GetType() string {
 	return m2m.OtherType
 }
 
+// orders by the number of related items, counting the same rows as the count filters
+func (m2m ManyToMany) GetOrderMap(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) map[string]schema.Query {
+	condition, args := countedCondition(c, m2m.OtherType, m2m.OtherIDColumn, nextArg)
+	orders := map[string]schema.Query{}
+	orders[m2m.Key+filters.COUNT_SUFFIX] = schema.Query{
+		Query: fmt.Sprintf(
+			"(select count(*) from %s as counted where counted.%s = %s.%s%s)",
+			m2m.Table,
+			m2m.OwnIDColumn,
+			modelTable,
+			idColumn,
+			condition,
+		),
+		Args: args,
+	}
+	return orders
+}
+
+func (m2m ManyToMany) GetFilterType() string {
+	return m2m.OtherType
+}
+func (m2m ManyToMany) GetFilterCondition(
+	modelTable string,
+	idColumn string,
+	relatedTable string,
+	relatedIDColumn string,
+) string {
+	return fmt.Sprintf(
+		"%s.%s in (select linked.%s from %s as linked where linked.%s = %s.%s)",
+		relatedTable,
+		relatedIDColumn,
+		m2m.OtherIDColumn,
+		m2m.Table,
+		m2m.OwnIDColumn,
+		modelTable,
+		idColumn,
+	)
+}
+
 func (m2m ManyToMany) GetDefaultValue() interface{} {
 	return schema.Page{}
 }
@@ -49,39 +97,30 @@ func (m2m ManyToMany) GetValues(
 	if otherModel == nil {
 		panic(fmt.Sprintf("Invalid other model %s", m2m.OtherType))
 	}
-	order, _, err := otherModel.GetOrderQuery(otherModel.DefaultOrder)
+	orderColumns, orderArgs, _, err := otherModel.GetOrderColumns(c, otherModel.DefaultOrder, len(ids)+1)
 	if err != nil {
 		panic(err)
 	}
 
+	// order columns may be expressions, so they are selected under aliases
 	orderSelects := ""
-	orderQuery := ""
-	orderArgsCombined := strings.TrimLeft(order, "order by ")
-	if len(orderArgsCombined) > 0 {
-		orderArgs := strings.Split(orderArgsCombined, ", ")
-		orderColumns := []string{}
-		for _, arg := range orderArgs {
-			column := strings.TrimSuffix(arg, " desc")
-			if column == otherModel.IDColumn || len(column) == 0 {
-				continue
-			}
-			orderColumns = append(orderColumns, column)
-		}
-
-		if len(orderArgs) > 0 {
-			if len(orderColumns) > 0 {
-				orderSelects = ", " + strings.Join(orderColumns, ", ")
-			}
-			orderQuery = "order by "
-			for index, arg := range orderArgs {
-				if index != 0 {
-					orderQuery += ", "
-				}
-				orderQuery += "sorters."
-				orderQuery += arg
-			}
+	sorterColumns := []schema.OrderColumn{}
+	for index, column := range orderColumns {
+		if column.Column == otherModel.IDColumn {
+			sorterColumns = append(sorterColumns, schema.OrderColumn{
+				Column:     "sorters." + otherModel.IDColumn,
+				Descending: column.Descending,
+			})
+			continue
 		}
+		alias := fmt.Sprintf("sorter_%d", index)
+		orderSelects += fmt.Sprintf(", %s as %s", column.Column, alias)
+		sorterColumns = append(sorterColumns, schema.OrderColumn{
+			Column:     "sorters." + alias,
+			Descending: column.Descending,
+		})
 	}
+	orderQuery := schema.OrderQuery(sorterColumns)
 
 	spots := []string{}
 	args := []interface{}{}
@@ -89,28 +128,38 @@ func (m2m ManyToMany) GetValues(
 		spots = append(spots, fmt.Sprintf("$%d", index+1))
 		args = append(args, id)
 	}
+	args = append(args, orderArgs...)
 	filter := fmt.Sprintf("%s in (%s)", m2m.OwnIDColumn, strings.Join(spots, ", "))
 	query := fmt.Sprintf(
 		`
-			select
-				relation.%s,
-				relation.%s
+			select own_id, other_id, total, position
 			from (
 				select
-					%s,
-					%s
-				from %s
-				where %s
-			) as relation
-			left join (
-				select %s%s from %s
-			) as sorters
-			on sorters.%s = relation.%s
-			%s
+					relation.%s as own_id,
+					relation.%s as other_id,
+					count(*) over (partition by relation.%s) as total,
+					row_number() over (partition by relation.%s %s) as position
+				from (
+					select
+						%s,
+						%s
+					from %s
+					where %s
+				) as relation
+				left join (
+					select %s%s from %s
+				) as sorters
+				on sorters.%s = relation.%s
+			) as numbered
+			where %s
+			order by own_id, position
 	    `,
 		m2m.OwnIDColumn,
 		m2m.OtherIDColumn,
 		m2m.OwnIDColumn,
+		m2m.OwnIDColumn,
+		orderQuery,
+		m2m.OwnIDColumn,
 		m2m.OtherIDColumn,
 		m2m.Table,
 		filter,
@@ -119,7 +168,7 @@ func (m2m ManyToMany) GetValues(
 		otherModel.Table,
 		otherModel.IDColumn,
 		m2m.OtherIDColumn,
-		orderQuery,
+		pageCondition("position", offset, pageSize),
 	)
 	rows, err := c.Query(query, args...)
 	if err != nil {
@@ -141,19 +190,21 @@ func (m2m ManyToMany) GetValues(
 	// go through result data
 	for rows.Next() {
 		var myID, otherID string
-		rows.Scan(&myID, &otherID)
+		var total, position int
+		err := rows.Scan(&myID, &otherID, &total, &position)
+		if err != nil {
+			panic(err)
+		}
 		value, exists := values[myID]
 		if !exists {
 			panic("Found unexpected id in results")
 		}
-
-		total, ok := value.Metadata["total"].(int)
-		if !ok {
-			panic("Bad total received")
-		}
-		count, ok := value.Metadata["count"].(int)
-		if !ok {
-			panic("Bad count received")
+		// counted across every page, as total
+		value.Metadata["count"] = total
+		value.Metadata["total"] = total
+		if !inPage(position, offset, pageSize) {
+			values[myID] = value
+			continue
 		}
 
 		_, exists = maps[myID]
@@ -163,19 +214,17 @@ func (m2m ManyToMany) GetValues(
 		}
 		maps[myID][m2m.OtherType] = append(maps[myID][m2m.OtherType], otherID)
 
-		if total >= offset && (pageSize < 0 || count < pageSize) {
-			count += 1
-			value.Data = append(value.Data, schema.InstancePointer{
-				ID:   &otherID,
-				Type: m2m.OtherType,
-			})
-			value.Metadata["count"] = count
-		}
-		total += 1
-		value.Metadata["total"] = total
+		value.Data = append(value.Data, schema.InstancePointer{
+			ID:   &otherID,
+			Type: m2m.OtherType,
+		})
 		// update the value
 		values[myID] = value
 	}
+	err = rows.Err()
+	if err != nil {
+		panic(err)
+	}
 	// create the links
 	for id, value := range values {
 		total, ok := value.Metadata["total"].(int)
@@ -190,6 +239,8 @@ func (m2m ManyToMany) GetValues(
 			total,
 			map[string][]string{},
 		)
+		relatedLink := relatedResourceLink(c, m.Type, id, m2m.Key)
+		value.Links["related"] = &relatedLink
 		values[id] = value
 	}
 	// generalise values
@@ -233,7 +284,7 @@ func (m2m *ManyToMany) Validate(c schema.Context, val interface{}) (interface{},
 	// validate the types are correct
 	for _, pointer := range m2mVal.Data {
 		if pointer.Type != m2m.OtherType {
-			return nil, errors.New(fmt.Sprintf(
+			return nil, utils.RelationshipError(m2m.Key, utils.CODE_INCORRECT_TYPE, fmt.Sprintf(
 				"Relationship '%s' invalid: Incorrect type in set.",
 				m2m.Key,
 			))
@@ -244,7 +295,7 @@ func (m2m *ManyToMany) Validate(c schema.Context, val interface{}) (interface{},
 	for _, pointer := range m2mVal.Data {
 		_, exists := ids[*pointer.ID]
 		if exists {
-			return nil, errors.New(fmt.Sprintf(
+			return nil, utils.RelationshipError(m2m.Key, utils.CODE_DUPLICATE, fmt.Sprintf(
 				"Relationship '%s' invalid: Duplicate object in set.",
 				m2m.Key,
 			))
@@ -267,7 +318,7 @@ func (m2m *ManyToMany) Validate(c schema.Context, val interface{}) (interface{},
 		panic(err)
 	}
 	if len(instances) < len(ids) {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, utils.RelationshipError(m2m.Key, utils.CODE_NOT_FOUND, fmt.Sprintf(
 			"Relationship '%s' invalid: Not all objects in set exist",
 			m2m.Key,
 		))
@@ -275,7 +326,7 @@ func (m2m *ManyToMany) Validate(c schema.Context, val interface{}) (interface{},
 	// check that the user has access to the objects
 	canAccess := c.CanAccessAllInstances(instances)
 	if !canAccess {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, utils.RelationshipError(m2m.Key, utils.CODE_FORBIDDEN, fmt.Sprintf(
 			"Relationship '%s' invalid: You do not have access to all objects in set.",
 			m2m.Key,
 		))
@@ -411,6 +462,21 @@ func (m2m *ManyToMany) GetUpdateQueries(
 	return queries
 }
 
+func (m2m *ManyToMany) GetDeleteQueries(id string) []schema.Query {
+	return []schema.Query{
+		schema.Query{
+			Query: fmt.Sprintf(
+				"delete from %s where %s = $1",
+				m2m.Table,
+				m2m.OwnIDColumn,
+			),
+			Args: []interface{}{
+				id,
+			},
+		},
+	}
+}
+
 func AssertManyToMany(val interface{}) schema.Page {
 	m2mVal, ok := val.(schema.Page)
 	if !ok {
@@ -56,6 +56,10 @@ func (f ManyToManyContainsFilter) GetWhere(
 		}
 		ids = append(ids, id)
 	}
+	err = rows.Err()
+	if err != nil {
+		panic(err)
+	}
 
 	if f.exclude {
 		if len(ids) > 0 {
@@ -196,6 +200,10 @@ func (f ManyToManyCountFilter) GetWhere(
 		}
 		ids = append(ids, id)
 	}
+	err = rows.Err()
+	if err != nil {
+		panic(err)
+	}
 
 	if len(ids) > 0 {
 		spots := []string{}
@@ -266,6 +274,7 @@ func (m2m ManyToMany) ValidateFilters(queries map[string][]string) ([]schema.Fil
 	if exists {
 		if len(exactCountStrings) != 1 {
 			return filters.Exception(
+				exactCountKey,
 				"Cannot compare count of relationship '%s' to more than one value.",
 				m2m.Key,
 			)
@@ -275,6 +284,7 @@ func (m2m ManyToMany) ValidateFilters(queries map[string][]string) ([]schema.Fil
 		intValue, err := strconv.Atoi(stringValue)
 		if err != nil {
 			return filters.Exception(
+				exactCountKey,
 				"Invalid count comparison value on relationship '%s'. Must be integer.",
 				m2m.Key,
 			)
@@ -299,6 +309,7 @@ func (m2m ManyToMany) ValidateFilters(queries map[string][]string) ([]schema.Fil
 	if exists {
 		if len(lesserCountStrings) != 1 {
 			return filters.Exception(
+				lesserCountKey,
 				"Cannot compare count of relationship '%s' to more than one value.",
 				m2m.Key,
 			)
@@ -308,6 +319,7 @@ func (m2m ManyToMany) ValidateFilters(queries map[string][]string) ([]schema.Fil
 		intValue, err := strconv.Atoi(stringValue)
 		if err != nil {
 			return filters.Exception(
+				lesserCountKey,
 				"Invalid count comparison value on relationship '%s'. Must be integer.",
 				m2m.Key,
 			)
@@ -332,6 +344,7 @@ func (m2m ManyToMany) ValidateFilters(queries map[string][]string) ([]schema.Fil
 	if exists {
 		if len(greaterCountStrings) != 1 {
 			return filters.Exception(
+				greaterCountKey,
 				"Cannot compare count of relationship '%s' to more than one value.",
 				m2m.Key,
 			)
@@ -341,6 +354,7 @@ func (m2m ManyToMany) ValidateFilters(queries map[string][]string) ([]schema.Fil
 		intValue, err := strconv.Atoi(stringValue)
 		if err != nil {
 			return filters.Exception(
+				greaterCountKey,
 				"Invalid count comparison value on relationship '%s'. Must be integer.",
 				m2m.Key,
 			)
@@ -2,9 +2,44 @@ package relationships
 
 import (
 	"errors"
+	"fmt"
 	"github.com/bor3ham/reja/schema"
+	"strings"
 )
 
+// to-many relations number their rows within each owner to page them in the query
+// the first row is always selected so owners past their last page still report a total
+func pageCondition(column string, offset int, pageSize int) string {
+	if pageSize < 0 {
+		return fmt.Sprintf("(%s > %d or %s = 1)", column, offset, column)
+	}
+	return fmt.Sprintf("((%s > %d and %s <= %d) or %s = 1)", column, offset, column, offset+pageSize, column)
+}
+
+func inPage(position int, offset int, pageSize int) bool {
+	return position > offset && (pageSize < 0 || position <= offset+pageSize)
+}
+
+// limits counted rows to related instances the user may see, given the column
+// of the counted table holding their ids
+func countedCondition(c schema.Context, relatedType string, linkColumn string, nextArg int) (string, []interface{}) {
+	related := c.GetServer().GetModel(relatedType)
+	if related == nil {
+		panic(fmt.Sprintf("Invalid related model %s", relatedType))
+	}
+	authQueries, authArgs := related.Manager.GetFilterForUser(c.GetUser(), nextArg)
+	if len(authQueries) == 0 {
+		return "", authArgs
+	}
+	return fmt.Sprintf(
+		" and counted.%s in (select %s from %s where %s)",
+		linkColumn,
+		related.IDColumn,
+		related.Table,
+		strings.Join(authQueries, " and "),
+	), authArgs
+}
+
 type Pointer struct {
 	Provided bool                    `json:"-"`
 	Data     *schema.InstancePointer `json:"data"`
@@ -103,9 +138,21 @@ func pointerSetFromPage(val interface{}) PointerSet {
 
 type RelationshipStub struct{}
 
+func (stub RelationshipStub) GetAccess() string {
+	return schema.ACCESS_READ_WRITE
+}
+
 func (stub RelationshipStub) GetSelectExtra() ([]string, []interface{}) {
 	return []string{}, []interface{}{}
 }
+func (stub RelationshipStub) GetOrderMap(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) map[string]schema.Query {
+	return map[string]schema.Query{}
+}
 func (stub RelationshipStub) AvailableFilters() []interface{} {
 	return []interface{}{}
 }
@@ -147,6 +194,9 @@ func (stub RelationshipStub) GetInsertQueries(newId string, val interface{}) []s
 func (stub RelationshipStub) GetUpdateQueries(id string, oldVal interface{}, newVal interface{}) []schema.Query {
 	return []schema.Query{}
 }
+func (stub RelationshipStub) GetDeleteQueries(id string) []schema.Query {
+	return []schema.Query{}
+}
 
 func AssertPointer(val interface{}) Pointer {
 	pointerVal, ok := val.(Pointer)
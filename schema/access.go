@@ -0,0 +1,34 @@
+package schema
+
+// how a field can be read and written through the api
+const ACCESS_READ_WRITE = ""
+
+// computed by the server, changes are rejected and values on create ignored
+const ACCESS_READ_ONLY = "read_only"
+
+// accepted on write but never rendered, filtered or ordered by
+const ACCESS_WRITE_ONLY = "write_only"
+
+// set when created, changes afterwards are rejected
+const ACCESS_CREATE_ONLY = "create_only"
+
+func ValidAccess(access string) bool {
+	return access == ACCESS_READ_WRITE ||
+		access == ACCESS_READ_ONLY ||
+		access == ACCESS_WRITE_ONLY ||
+		access == ACCESS_CREATE_ONLY
+}
+
+func Readable(access string) bool {
+	return access != ACCESS_WRITE_ONLY
+}
+
+// whether the field can be given a value on create
+func Creatable(access string) bool {
+	return access != ACCESS_READ_ONLY
+}
+
+// whether the field can be changed once created
+func Updatable(access string) bool {
+	return access != ACCESS_READ_ONLY && access != ACCESS_CREATE_ONLY
+}
@@ -2,6 +2,7 @@ package schema
 
 type Attribute interface {
 	GetKey() string
+	GetAccess() string
 
 	GetSelectDirect() ([]string, []interface{})
 
@@ -0,0 +1,134 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/gob"
+	"time"
+)
+
+// shared storage for serialised instances between requests, keyed by type and id
+type InstanceCache interface {
+	Get(modelType string, id string) ([]byte, bool)
+	Set(modelType string, id string, blob []byte, ttl time.Duration)
+	// forgets every instance of a type
+	Invalidate(modelType string)
+	Stats() CacheStats
+}
+
+type CacheStats struct {
+	Hits    int64
+	Misses  int64
+	Entries int
+}
+
+// values held in interfaces must be registered to be serialised
+// custom attribute values should register themselves the same way
+func init() {
+	gob.Register(InstancePointer{})
+	gob.Register(Result{})
+	gob.Register(Page{})
+}
+
+// links may be null, which gob cannot encode within a map
+type gobLinks struct {
+	Present bool
+	Links   map[string]string
+	Null    []string
+}
+
+func encodeLinks(links map[string]*string) gobLinks {
+	encoded := gobLinks{
+		Present: links != nil,
+		Links:   map[string]string{},
+	}
+	for key, link := range links {
+		if link == nil {
+			encoded.Null = append(encoded.Null, key)
+		} else {
+			encoded.Links[key] = *link
+		}
+	}
+	return encoded
+}
+func decodeLinks(encoded gobLinks) map[string]*string {
+	if !encoded.Present {
+		return nil
+	}
+	links := map[string]*string{}
+	for key, link := range encoded.Links {
+		value := link
+		links[key] = &value
+	}
+	for _, key := range encoded.Null {
+		links[key] = nil
+	}
+	return links
+}
+
+type gobResult struct {
+	Provided bool
+	Links    gobLinks
+	Data     interface{}
+}
+
+func (r Result) GobEncode() ([]byte, error) {
+	var buffer bytes.Buffer
+	err := gob.NewEncoder(&buffer).Encode(gobResult{
+		Provided: r.Provided,
+		Links:    encodeLinks(r.Links),
+		Data:     r.Data,
+	})
+	return buffer.Bytes(), err
+}
+func (r *Result) GobDecode(data []byte) error {
+	var decoded gobResult
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded)
+	if err != nil {
+		return err
+	}
+	r.Provided = decoded.Provided
+	r.Links = decodeLinks(decoded.Links)
+	r.Data = decoded.Data
+	return nil
+}
+
+// empty and missing metadata and data are output differently, so both are kept
+type gobPage struct {
+	Provided    bool
+	HasMetadata bool
+	Metadata    map[string]interface{}
+	Links       gobLinks
+	HasData     bool
+	Data        []interface{}
+}
+
+func (p Page) GobEncode() ([]byte, error) {
+	var buffer bytes.Buffer
+	err := gob.NewEncoder(&buffer).Encode(gobPage{
+		Provided:    p.Provided,
+		HasMetadata: p.Metadata != nil,
+		Metadata:    p.Metadata,
+		Links:       encodeLinks(p.Links),
+		HasData:     p.Data != nil,
+		Data:        p.Data,
+	})
+	return buffer.Bytes(), err
+}
+func (p *Page) GobDecode(data []byte) error {
+	var decoded gobPage
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded)
+	if err != nil {
+		return err
+	}
+	p.Provided = decoded.Provided
+	p.Metadata = decoded.Metadata
+	if decoded.HasMetadata && p.Metadata == nil {
+		p.Metadata = map[string]interface{}{}
+	}
+	p.Links = decodeLinks(decoded.Links)
+	p.Data = decoded.Data
+	if decoded.HasData && p.Data == nil {
+		p.Data = []interface{}{}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
 )
@@ -8,6 +9,8 @@ import (
 type Context interface {
 	GetServer() Server
 	GetRequest() *http.Request
+	// cancelled when the client goes away or the statement timeout passes
+	GetContext() context.Context
 	WriteToResponse(blob interface{})
 
 	SetUser(User)
@@ -30,6 +33,7 @@ type Context interface {
 		[]string,
 		[]interface{},
 		string,
+		[]interface{},
 		int,
 		int,
 		*Include,
@@ -40,4 +44,5 @@ type Context interface {
 	)
 
 	CanAccessAllInstances([]Instance) bool
+	CanWriteAllInstances([]Instance) bool
 }
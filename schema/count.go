@@ -0,0 +1,10 @@
+package schema
+
+// how list endpoints calculate their total
+const COUNT_EXACT = "exact"
+const COUNT_NONE = "none"
+const COUNT_ESTIMATE = "estimate"
+
+func ValidCountPolicy(policy string) bool {
+	return policy == COUNT_EXACT || policy == COUNT_NONE || policy == COUNT_ESTIMATE
+}
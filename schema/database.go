@@ -1,14 +1,16 @@
 package schema
 
 import (
+	"context"
 	"database/sql"
 )
 
+// statements are tied to the request, so they stop once it is cancelled or times out
 type Database interface {
-	QueryRow(string, ...interface{}) *sql.Row
-	Query(string, ...interface{}) (*sql.Rows, error)
-	Exec(string, ...interface{}) (sql.Result, error)
-	Begin() (*sql.Tx, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
 }
 
 type Transaction interface {
@@ -2,6 +2,8 @@ package schema
 
 type Include struct {
 	Children map[string]*Include
+	// sparse fieldsets by model type, shared by every node in the tree
+	Fields map[string][]string
 }
 
 func (i *Include) endNodes(prefix string) []string {
@@ -27,3 +29,48 @@ func (i *Include) AsString() string {
 	}
 	return query
 }
+
+func (i *Include) SetFields(fields map[string][]string) {
+	i.Fields = fields
+	for _, child := range i.Children {
+		child.SetFields(fields)
+	}
+}
+
+func (i *Include) Restricts(modelType string) bool {
+	if i == nil || i.Fields == nil {
+		return false
+	}
+	_, restricted := i.Fields[modelType]
+	return restricted
+}
+
+func (i *Include) Selects(modelType string, key string) bool {
+	if !i.Restricts(modelType) {
+		return true
+	}
+	for _, field := range i.Fields[modelType] {
+		if field == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *Include) FieldsAsQueries() map[string][]string {
+	queries := map[string][]string{}
+	if i == nil {
+		return queries
+	}
+	for modelType, fields := range i.Fields {
+		query := ""
+		for index, field := range fields {
+			if index != 0 {
+				query += ","
+			}
+			query += field
+		}
+		queries["fields["+modelType+"]"] = []string{query}
+	}
+	return queries
+}
@@ -4,9 +4,35 @@ type Manager interface {
 	Create() Instance
 	GetFilterForUser(User, int) ([]string, []interface{})
 
+	// write authorization, on top of the read filter
+	// refuse with utils.Forbidden() or any other error
+	GetWriteFilterForUser(User, int) ([]string, []interface{})
+	CanCreate(User) error
+	CanUpdate(User, Instance) error
+	CanDelete(User, Instance) error
+
+	// field level authorization, hidden fields are left out of responses
+	// and changes to unwritable fields are rejected, new instances have no id yet
+	// the instance is nil when a field is checked for a whole query, such as
+	// when filtering, ordering or including through it
+	CanReadField(User, Instance, string) bool
+	CanWriteField(User, Instance, string) bool
+
+	// validation run before any changes are written
 	BeforeCreate(Context, map[string]interface{}) error
 	BeforeUpdate(Context, map[string]interface{}, map[string]interface{}) error
 	BeforeSave(Context, map[string]interface{}) error
+	BeforeDelete(Context, string, map[string]interface{}) error
+
+	// run inside the transaction after changes are written, an error rolls back
+	DuringCreate(Context, Transaction, string, map[string]interface{}) error
+	DuringUpdate(Context, Transaction, string, map[string]interface{}, map[string]interface{}) error
+	DuringDelete(Context, Transaction, string, map[string]interface{}) error
+
+	// run once the transaction has been committed
+	AfterCreate(Context, string, map[string]interface{})
+	AfterUpdate(Context, string, map[string]interface{}, map[string]interface{})
+	AfterDelete(Context, string, map[string]interface{})
 }
 
 type ManagerStub struct {
@@ -16,6 +42,26 @@ func (stub ManagerStub) GetFilterForUser(user User, nextArg int) ([]string, []in
 	return []string{}, []interface{}{}
 }
 
+func (stub ManagerStub) GetWriteFilterForUser(user User, nextArg int) ([]string, []interface{}) {
+	return []string{}, []interface{}{}
+}
+func (stub ManagerStub) CanCreate(user User) error {
+	return nil
+}
+func (stub ManagerStub) CanUpdate(user User, instance Instance) error {
+	return nil
+}
+func (stub ManagerStub) CanDelete(user User, instance Instance) error {
+	return nil
+}
+
+func (stub ManagerStub) CanReadField(user User, instance Instance, key string) bool {
+	return true
+}
+func (stub ManagerStub) CanWriteField(user User, instance Instance, key string) bool {
+	return true
+}
+
 func (stub ManagerStub) BeforeCreate(c Context, values map[string]interface{}) error {
 	return nil
 }
@@ -29,3 +75,44 @@ func (stub ManagerStub) BeforeUpdate(
 func (stub ManagerStub) BeforeSave(c Context, values map[string]interface{}) error {
 	return nil
 }
+func (stub ManagerStub) BeforeDelete(c Context, id string, values map[string]interface{}) error {
+	return nil
+}
+
+func (stub ManagerStub) DuringCreate(
+	c Context,
+	tx Transaction,
+	id string,
+	values map[string]interface{},
+) error {
+	return nil
+}
+func (stub ManagerStub) DuringUpdate(
+	c Context,
+	tx Transaction,
+	id string,
+	oldValues map[string]interface{},
+	newValues map[string]interface{},
+) error {
+	return nil
+}
+func (stub ManagerStub) DuringDelete(
+	c Context,
+	tx Transaction,
+	id string,
+	values map[string]interface{},
+) error {
+	return nil
+}
+
+func (stub ManagerStub) AfterCreate(c Context, id string, values map[string]interface{}) {
+}
+func (stub ManagerStub) AfterUpdate(
+	c Context,
+	id string,
+	oldValues map[string]interface{},
+	newValues map[string]interface{},
+) {
+}
+func (stub ManagerStub) AfterDelete(c Context, id string, values map[string]interface{}) {
+}
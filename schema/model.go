@@ -4,8 +4,16 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
+// stands for the model table within computed sql expressions
+const TABLE_PLACEHOLDER = "{table}"
+
+func TableExpression(expression string, table string) string {
+	return strings.Replace(expression, TABLE_PLACEHOLDER, table, -1)
+}
+
 type Model struct {
 	Type          string
 	Table         string
@@ -15,6 +23,93 @@ type Model struct {
 	Attributes    []Attribute
 	Relationships []Relationship
 	Manager       Manager
+
+	// page lists by cursor instead of offset, without a total count by default
+	KeysetPagination bool
+	// overrides the server count policy for lists of this model
+	CountPolicy string
+	// limits the http methods served for this model, all are served if empty
+	Methods []string
+	// overrides the server rate limit by method, or RATE_LIMIT_ANY
+	RateLimits map[string]RateLimit
+	// overrides the server instance cache ttl, negative to leave this model uncached
+	CacheTTL time.Duration
+	// a column changed on every update, such as a version number or updated at
+	// timestamp, used to tag responses instead of hashing them
+	VersionColumn string
+}
+
+// the rate limit for a method on this model, falling back to the given default
+// returns the key of the limit so buckets are shared between methods under the same limit
+func (m Model) RateLimitFor(method string, fallback RateLimit) (string, RateLimit) {
+	limit, exists := m.RateLimits[method]
+	if exists {
+		return method, limit
+	}
+	limit, exists = m.RateLimits[RATE_LIMIT_ANY]
+	if exists {
+		return RATE_LIMIT_ANY, limit
+	}
+	return "", fallback
+}
+
+func (m Model) AllowsMethod(method string) bool {
+	if len(m.Methods) == 0 {
+		return true
+	}
+	for _, allowed := range m.Methods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+// the access mode of the field with the given key
+func (m Model) FieldAccess(key string) string {
+	for _, attribute := range m.Attributes {
+		if attribute.GetKey() == key {
+			return attribute.GetAccess()
+		}
+	}
+	for _, relationship := range m.Relationships {
+		if relationship.GetKey() == key {
+			return relationship.GetAccess()
+		}
+	}
+	return ACCESS_READ_WRITE
+}
+
+// whether the user may read the field on any instance, so can query through it
+func (m Model) FieldVisible(user User, key string) bool {
+	return Readable(m.FieldAccess(key)) && m.Manager.CanReadField(user, nil, key)
+}
+
+// the keys of every field that can be rendered
+func (m Model) ReadableFields() []string {
+	fields := []string{}
+	for _, attribute := range m.Attributes {
+		if Readable(attribute.GetAccess()) {
+			fields = append(fields, attribute.GetKey())
+		}
+	}
+	for _, relationship := range m.Relationships {
+		if Readable(relationship.GetAccess()) {
+			fields = append(fields, relationship.GetKey())
+		}
+	}
+	return fields
+}
+
+// filters the given methods to those the model allows
+func (m Model) AllowedMethods(methods ...string) []string {
+	allowed := []string{}
+	for _, method := range methods {
+		if m.AllowsMethod(method) {
+			allowed = append(allowed, method)
+		}
+	}
+	return allowed
 }
 
 func (m Model) DirectFields() ([]string, []interface{}) {
@@ -22,7 +117,9 @@ func (m Model) DirectFields() ([]string, []interface{}) {
 	var allVars []interface{}
 	for _, attribute := range m.Attributes {
 		columns, vars := attribute.GetSelectDirect()
-		allColumns = append(allColumns, columns...)
+		for _, column := range columns {
+			allColumns = append(allColumns, TableExpression(column, m.Table))
+		}
 		allVars = append(allVars, vars...)
 	}
 	return allColumns, allVars
@@ -39,20 +136,114 @@ func (m Model) ExtraFields() ([]string, [][]interface{}) {
 	return allColumns, allVars
 }
 
-func (m Model) GetOrderQuery(asParam string) (string, string, error) {
-	validParam := ""
+type OrderColumn struct {
+	Column     string
+	Descending bool
+}
+
+// whether the field can be ordered by, checked against the user only when the
+// client chose the order so default orders on hidden fields still apply
+func (m Model) orderable(user User, key string, chosen bool) bool {
+	if chosen {
+		return m.FieldVisible(user, key)
+	}
+	return Readable(m.FieldAccess(key))
+}
 
+// the orderable keys of the model, with expressions referring to the given table
+func (m Model) orderMap(user User, table string, chosen bool) map[string]string {
 	validOrders := map[string]string{
 		"id": m.IDColumn,
 	}
 	for _, attribute := range m.Attributes {
+		// ordering would reveal write only or hidden values
+		if !m.orderable(user, attribute.GetKey(), chosen) {
+			continue
+		}
 		attrOrders := attribute.GetOrderMap()
 		for key, arg := range attrOrders {
-			validOrders[key] = arg
+			validOrders[key] = TableExpression(arg, table)
 		}
 	}
+	return validOrders
+}
 
-	queryArgs := []string{}
+// orders by a key of the model or of its relationships, such as tags__count
+func (m Model) keyOrder(c Context, table string, key string, nextArg int, chosen bool) (string, []interface{}, bool) {
+	column, exists := m.orderMap(c.GetUser(), table, chosen)[key]
+	if exists {
+		return column, []interface{}{}, true
+	}
+	for _, relationship := range m.Relationships {
+		if !m.orderable(c.GetUser(), relationship.GetKey(), chosen) {
+			continue
+		}
+		order, exists := relationship.GetOrderMap(c, table, m.IDColumn, nextArg)[key]
+		if exists {
+			return order.Query, order.Args, true
+		}
+	}
+	return "", []interface{}{}, false
+}
+
+// the alias of the related table when ordering through a relationship
+const ORDER_RELATED_ALIAS = "ordered"
+
+// orders by a field of the instance a relationship points to, such as author.name
+// instances hidden from the user sort as if they were missing
+func (m Model) relatedOrder(c Context, key string, nextArg int, chosen bool) (string, []interface{}, bool) {
+	parts := strings.SplitN(key, ".", 2)
+	for _, relationship := range m.Relationships {
+		if relationship.GetKey() != parts[0] || !m.orderable(c.GetUser(), parts[0], chosen) {
+			continue
+		}
+		through, ok := relationship.(OrderingRelationship)
+		if !ok || len(through.GetOrderType()) == 0 {
+			return "", []interface{}{}, false
+		}
+		related := c.GetServer().GetModel(through.GetOrderType())
+		if related == nil {
+			return "", []interface{}{}, false
+		}
+		column, columnArgs, exists := related.keyOrder(c, ORDER_RELATED_ALIAS, parts[1], nextArg, chosen)
+		if !exists {
+			return "", []interface{}{}, false
+		}
+
+		conditions := []string{
+			through.GetOrderCondition(m.Table, ORDER_RELATED_ALIAS+"."+related.IDColumn),
+		}
+		authQueries, authArgs := related.Manager.GetFilterForUser(c.GetUser(), nextArg+len(columnArgs))
+		if len(authQueries) > 0 {
+			conditions = append(conditions, fmt.Sprintf(
+				"%s.%s in (select %s from %s where %s)",
+				ORDER_RELATED_ALIAS,
+				related.IDColumn,
+				related.IDColumn,
+				related.Table,
+				strings.Join(authQueries, " and "),
+			))
+		}
+		return fmt.Sprintf(
+			"(select %s from %s as %s where %s)",
+			column,
+			related.Table,
+			ORDER_RELATED_ALIAS,
+			strings.Join(conditions, " and "),
+		), append(columnArgs, authArgs...), true
+	}
+	return "", []interface{}{}, false
+}
+
+// orders by the given keys, with any arguments numbered from nextArg
+func (m Model) GetOrderColumns(c Context, asParam string, nextArg int) ([]OrderColumn, []interface{}, string, error) {
+	validParam := ""
+	orderArgs := []interface{}{}
+
+	// the default order is the model's own rather than the client's
+	chosen := asParam != m.DefaultOrder
+
+	orderColumns := []OrderColumn{}
 	splitOrders := strings.Split(asParam, ",")
 	orderedColumns := map[string]bool{}
 	for _, order := range splitOrders {
@@ -61,32 +252,57 @@ func (m Model) GetOrderQuery(asParam string) (string, string, error) {
 			continue
 		}
 		posCleanOrder := strings.TrimPrefix(cleanOrder, "-")
-		column, exists := validOrders[posCleanOrder]
+		column, columnArgs, exists := m.keyOrder(c, m.Table, posCleanOrder, nextArg+len(orderArgs), chosen)
+		if !exists && strings.Contains(posCleanOrder, ".") {
+			column, columnArgs, exists = m.relatedOrder(c, posCleanOrder, nextArg+len(orderArgs), chosen)
+		}
 		if !exists {
-			return "", "", errors.New(fmt.Sprintf(
+			return []OrderColumn{}, []interface{}{}, "", errors.New(fmt.Sprintf(
 				"Cannot order by unknown field '%s'.",
 				cleanOrder,
 			))
 		}
-		_, exists = orderedColumns[column]
+		// arguments are numbered afresh each time, so the key identifies related columns
+		orderedKey := column
+		if len(columnArgs) > 0 {
+			orderedKey = posCleanOrder
+		}
+		_, exists = orderedColumns[orderedKey]
 		if exists {
-			return "", "", errors.New(fmt.Sprintf(
+			return []OrderColumn{}, []interface{}{}, "", errors.New(fmt.Sprintf(
 				"Cannot order by column '%s' twice.",
 				cleanOrder,
 			))
 		}
-		orderedColumns[column] = true
-		query := column
-		if posCleanOrder != cleanOrder {
-			query += " desc"
-		}
-		queryArgs = append(queryArgs, query)
+		orderedColumns[orderedKey] = true
+		orderArgs = append(orderArgs, columnArgs...)
+		orderColumns = append(orderColumns, OrderColumn{
+			Column:     column,
+			Descending: posCleanOrder != cleanOrder,
+		})
 		if len(validParam) != 0 {
 			validParam += ","
 		}
 		validParam += cleanOrder
 	}
 
+	if validParam == m.DefaultOrder {
+		validParam = ""
+	}
+
+	return orderColumns, orderArgs, validParam, nil
+}
+
+func OrderQuery(orderColumns []OrderColumn) string {
+	queryArgs := []string{}
+	for _, order := range orderColumns {
+		query := order.Column
+		if order.Descending {
+			query += " desc"
+		}
+		queryArgs = append(queryArgs, query)
+	}
+
 	query := ""
 	if len(queryArgs) > 0 {
 		query = fmt.Sprintf(
@@ -94,10 +310,13 @@ func (m Model) GetOrderQuery(asParam string) (string, string, error) {
 			strings.Join(queryArgs, ", "),
 		)
 	}
+	return query
+}
 
-	if validParam == m.DefaultOrder {
-		validParam = ""
+func (m Model) GetOrderQuery(c Context, asParam string, nextArg int) (string, []interface{}, string, error) {
+	orderColumns, orderArgs, validParam, err := m.GetOrderColumns(c, asParam, nextArg)
+	if err != nil {
+		return "", []interface{}{}, "", err
 	}
-
-	return query, validParam, nil
+	return OrderQuery(orderColumns), orderArgs, validParam, nil
 }
@@ -39,3 +39,11 @@ func (p Page) IDs() []string {
 	}
 	return ids
 }
+
+func AssertPage(value interface{}) Page {
+	page, ok := value.(Page)
+	if !ok {
+		panic("Bad page")
+	}
+	return page
+}
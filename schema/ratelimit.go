@@ -0,0 +1,32 @@
+package schema
+
+import (
+	"time"
+)
+
+// rate limits keyed by method apply to that method only, otherwise to any method
+const RATE_LIMIT_ANY = "*"
+
+// a token bucket holding Requests tokens, refilled completely over Per
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+func (limit RateLimit) Enabled() bool {
+	return limit.Requests > 0 && limit.Per > 0
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// until another request would be allowed
+	RetryAfter time.Duration
+	// until the bucket is full again
+	Reset time.Duration
+}
+
+// storage for token buckets, shared by every request to the server
+type RateLimiter interface {
+	Take(key string, limit RateLimit) RateLimitResult
+}
@@ -1,11 +1,33 @@
 package schema
 
+// relationships pointing to at most one instance of a single type, whose fields
+// can be ordered by
+type OrderingRelationship interface {
+	// the related type, or empty if there could be several
+	GetOrderType() string
+	// matches the qualified id column of the related instance to the owning row
+	GetOrderCondition(modelTable string, relatedID string) string
+}
+
+// relationships whose related instances can be filtered on, such as author.name=foo
+type FilteringRelationship interface {
+	// the related type, or empty if there could be several
+	GetFilterType() string
+	// matches rows of the related table to the owning row
+	GetFilterCondition(modelTable string, idColumn string, relatedTable string, relatedIDColumn string) string
+}
+
 type Relationship interface {
 	GetKey() string
 	GetType() string
+	GetAccess() string
 
 	GetSelectExtra() ([]string, []interface{})
 
+	// given the table and id column of the owning model, with any arguments
+	// numbered from the given one
+	GetOrderMap(Context, string, string, int) map[string]Query
+
 	AvailableFilters() []interface{}
 	ValidateFilters(map[string][]string) ([]Filter, error)
 	GetFilterWhere(int, map[string][]string) ([]string, []interface{})
@@ -26,6 +48,7 @@ type Relationship interface {
 	GetInsert(interface{}) ([]string, []interface{})
 	GetInsertQueries(string, interface{}) []Query
 	GetUpdateQueries(string, interface{}, interface{}) []Query
+	GetDeleteQueries(string) []Query
 
 	DefaultFallback(Context, interface{}, interface{}) (interface{}, error)
 	Validate(Context, interface{}) (interface{}, error)
@@ -2,6 +2,7 @@ package schema
 
 import (
 	"net/http"
+	"time"
 )
 
 type Server interface {
@@ -10,12 +11,26 @@ type Server interface {
 	GetDefaultDirectPageSize() int
 	GetMaximumDirectPageSize() int
 	GetIndirectPageSize() int
+	GetDefaultCountPolicy() string
+	GetStatementTimeout() time.Duration
+	GetMaximumWorkers() int
+
+	GetInstanceCache() InstanceCache
+	GetDefaultCacheTTL() time.Duration
+	InvalidateCache(string)
+	GetCacheGeneration(string) uint64
+
+	GetRateLimiter() RateLimiter
+	GetDefaultRateLimit() RateLimit
+	GetAuthenticationRateLimit() RateLimit
+	GetRateLimitKey(Context) string
 
 	GetModel(string) *Model
 	GetRoute(string) string
 
 	Whitespace() bool
 	UseEasyJSON() bool
+	UseSnapshotReads() bool
 	LogSQL() bool
 
 	Authenticate(http.ResponseWriter, *http.Request, Context) (User, error)
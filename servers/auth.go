@@ -5,10 +5,21 @@ import (
 	"github.com/bor3ham/reja/schema"
 	"github.com/bor3ham/reja/utils"
 	// "github.com/davecgh/go-spew/spew"
+	"net/http"
+	"strconv"
 	"strings"
 )
 
 func (rc *RequestContext) CanAccessAllInstances(instances []schema.Instance) bool {
+	return rc.instancesMatchFilters(instances, false)
+}
+
+// instances can only be written if they can also be read
+func (rc *RequestContext) CanWriteAllInstances(instances []schema.Instance) bool {
+	return rc.instancesMatchFilters(instances, true)
+}
+
+func (rc *RequestContext) instancesMatchFilters(instances []schema.Instance, write bool) bool {
 	typeMap := map[string][]string{}
 	for _, instance := range instances {
 		instanceType := instance.GetType()
@@ -38,6 +49,11 @@ func (rc *RequestContext) CanAccessAllInstances(instances []schema.Instance) boo
 		}
 
 		authFilters, authArgs := model.Manager.GetFilterForUser(rc.GetUser(), nextArg)
+		if write {
+			writeFilters, writeArgs := model.Manager.GetWriteFilterForUser(rc.GetUser(), nextArg+len(authArgs))
+			authFilters = append(authFilters, writeFilters...)
+			authArgs = append(authArgs, writeArgs...)
+		}
 		if len(authFilters) > 0 {
 			query += " and " + strings.Join(authFilters, " and ")
 			args = append(args, authArgs...)
@@ -53,6 +69,10 @@ func (rc *RequestContext) CanAccessAllInstances(instances []schema.Instance) boo
 			rows.Scan(&id)
 			resultIds = append(resultIds, id)
 		}
+		err = rows.Err()
+		if err != nil {
+			panic(err)
+		}
 
 		for _, id := range ids {
 			found := false
@@ -69,3 +89,56 @@ func (rc *RequestContext) CanAccessAllInstances(instances []schema.Instance) boo
 
 	return true
 }
+
+// converts a refusal from the manager into a request error
+func forbiddenError(err error) RequestError {
+	status := http.StatusForbidden
+	authError, ok := err.(utils.AuthError)
+	if ok {
+		status = authError.Status
+	}
+	return RequestError{
+		Status: status,
+		Exceptions: []Exception{
+			Exception{
+				Status: strconv.Itoa(status),
+				Code:   utils.CODE_FORBIDDEN,
+				Title:  http.StatusText(status),
+				Detail: err.Error(),
+			},
+		},
+	}
+}
+
+// checks the user may create instances of the model
+func authoriseCreate(c schema.Context, m *schema.Model) error {
+	err := m.Manager.CanCreate(c.GetUser())
+	if err != nil {
+		return forbiddenError(err)
+	}
+	return nil
+}
+
+// checks the user may change an existing instance
+func authoriseUpdate(c schema.Context, m *schema.Model, instance schema.Instance) error {
+	if !c.CanWriteAllInstances([]schema.Instance{instance}) {
+		return forbiddenError(utils.Forbidden())
+	}
+	err := m.Manager.CanUpdate(c.GetUser(), instance)
+	if err != nil {
+		return forbiddenError(err)
+	}
+	return nil
+}
+
+// checks the user may delete an existing instance
+func authoriseDelete(c schema.Context, m *schema.Model, instance schema.Instance) error {
+	if !c.CanWriteAllInstances([]schema.Instance{instance}) {
+		return forbiddenError(utils.Forbidden())
+	}
+	err := m.Manager.CanDelete(c.GetUser(), instance)
+	if err != nil {
+		return forbiddenError(err)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package servers
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/gob"
+	"github.com/bor3ham/reja/schema"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type memoryCacheEntry struct {
+	modelType string
+	id        string
+	blob      []byte
+	expires   time.Time
+}
+
+// an in memory cache holding up to capacity instances, discarding the least recently used
+type MemoryInstanceCache struct {
+	mutex    sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+	types    map[string]map[string]*list.Element
+	hits     int64
+	misses   int64
+}
+
+func NewMemoryInstanceCache(capacity int) *MemoryInstanceCache {
+	return &MemoryInstanceCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+		types:    map[string]map[string]*list.Element{},
+	}
+}
+
+func memoryCacheKey(modelType string, id string) string {
+	return modelType + ":" + id
+}
+
+func (cache *MemoryInstanceCache) Get(modelType string, id string) ([]byte, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	element, exists := cache.entries[memoryCacheKey(modelType, id)]
+	if exists {
+		entry := element.Value.(*memoryCacheEntry)
+		if entry.expires.IsZero() || time.Now().Before(entry.expires) {
+			cache.order.MoveToFront(element)
+			cache.hits += 1
+			return entry.blob, true
+		}
+		cache.remove(element)
+	}
+	cache.misses += 1
+	return nil, false
+}
+
+func (cache *MemoryInstanceCache) Set(modelType string, id string, blob []byte, ttl time.Duration) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+	key := memoryCacheKey(modelType, id)
+	element, exists := cache.entries[key]
+	if exists {
+		entry := element.Value.(*memoryCacheEntry)
+		entry.blob = blob
+		entry.expires = expires
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	element = cache.order.PushFront(&memoryCacheEntry{
+		modelType: modelType,
+		id:        id,
+		blob:      blob,
+		expires:   expires,
+	})
+	cache.entries[key] = element
+	_, exists = cache.types[modelType]
+	if !exists {
+		cache.types[modelType] = map[string]*list.Element{}
+	}
+	cache.types[modelType][id] = element
+
+	for cache.capacity > 0 && cache.order.Len() > cache.capacity {
+		cache.remove(cache.order.Back())
+	}
+}
+
+func (cache *MemoryInstanceCache) Invalidate(modelType string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	for _, element := range cache.types[modelType] {
+		cache.remove(element)
+	}
+}
+
+func (cache *MemoryInstanceCache) Stats() schema.CacheStats {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return schema.CacheStats{
+		Hits:    cache.hits,
+		Misses:  cache.misses,
+		Entries: cache.order.Len(),
+	}
+}
+
+// must be called with the mutex held
+func (cache *MemoryInstanceCache) remove(element *list.Element) {
+	entry := element.Value.(*memoryCacheEntry)
+	cache.order.Remove(element)
+	delete(cache.entries, memoryCacheKey(entry.modelType, entry.id))
+	delete(cache.types[entry.modelType], entry.id)
+}
+
+// the serialised form of an instance in the shared cache
+type sharedInstance struct {
+	Values      map[string]interface{}
+	RelationMap map[string]map[string][]string
+}
+
+// the shared cache is skipped within transactions, which may hold uncommitted
+// changes or a snapshot older than the cache
+func (rc *RequestContext) sharedCache(m *schema.Model) (schema.InstanceCache, time.Duration) {
+	cache := rc.Server.GetInstanceCache()
+	if cache == nil || rc.boundTx != nil {
+		return nil, 0
+	}
+	ttl := m.CacheTTL
+	if ttl == 0 {
+		ttl = rc.Server.GetDefaultCacheTTL()
+	}
+	if ttl < 0 {
+		return nil, 0
+	}
+	return cache, ttl
+}
+
+func (rc *RequestContext) getSharedObject(
+	m *schema.Model,
+	id string,
+) (
+	schema.Instance,
+	map[string]map[string][]string,
+) {
+	cache, _ := rc.sharedCache(m)
+	if cache == nil {
+		return nil, nil
+	}
+	blob, exists := cache.Get(m.Type, id)
+	if !exists {
+		atomic.AddInt64(&rc.sharedMisses, 1)
+		return nil, nil
+	}
+	var shared sharedInstance
+	err := gob.NewDecoder(bytes.NewReader(blob)).Decode(&shared)
+	if err != nil {
+		// treat unreadable entries as missing
+		atomic.AddInt64(&rc.sharedMisses, 1)
+		return nil, nil
+	}
+	atomic.AddInt64(&rc.sharedHits, 1)
+	instance := m.Manager.Create()
+	instance.SetID(id)
+	instance.SetValues(shared.Values)
+	return instance, shared.RelationMap
+}
+
+// skipped if the model was invalidated since the generation the instance was read at
+func (rc *RequestContext) storeSharedObject(
+	m *schema.Model,
+	instance schema.Instance,
+	relationMap map[string]map[string][]string,
+	generation uint64,
+) {
+	cache, ttl := rc.sharedCache(m)
+	if cache == nil || rc.Server.GetCacheGeneration(m.Type) != generation {
+		return
+	}
+	var buffer bytes.Buffer
+	err := gob.NewEncoder(&buffer).Encode(sharedInstance{
+		Values:      instance.GetValues(),
+		RelationMap: relationMap,
+	})
+	if err != nil {
+		// values that cannot be serialised are left uncached
+		log.Printf("Unable to cache %s %s: %v", m.Type, instance.GetID(), err)
+		return
+	}
+	cache.Set(m.Type, instance.GetID(), buffer.Bytes(), ttl)
+	// an invalidation may have come between checking and storing
+	if rc.Server.GetCacheGeneration(m.Type) != generation {
+		cache.Invalidate(m.Type)
+	}
+}
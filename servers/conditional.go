@@ -0,0 +1,264 @@
+package servers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// holds back the response to a GET so it can be tagged, or replaced with a 304
+type conditionalResponseWriter struct {
+	http.ResponseWriter
+	request *http.Request
+	status  int
+	body    bytes.Buffer
+	// the instance state to tag the body with, if a single instance
+	state string
+}
+
+func (w *conditionalResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+func (w *conditionalResponseWriter) Write(body []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(body)
+}
+
+// discards a partly written response so an error can be sent instead
+func (w *conditionalResponseWriter) reset() {
+	w.status = 0
+	w.body.Reset()
+	w.state = ""
+}
+
+// writes the held response, unless the client already has it
+func (w *conditionalResponseWriter) flush() {
+	if w.status == 0 {
+		return
+	}
+	header := w.Header()
+	if w.status == http.StatusOK {
+		if len(header.Get("ETag")) == 0 {
+			if len(w.state) > 0 {
+				header.Set("ETag", instanceETag(w.state, hashTag(w.body.String())))
+			} else {
+				header.Set("ETag", bodyETag(w.body.Bytes()))
+			}
+		}
+		if notModified(w.request, header) {
+			w.ResponseWriter.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(w.body.Bytes())
+}
+
+func bodyETag(body []byte) string {
+	return fmt.Sprintf(`"%s"`, hashTag(string(body)))
+}
+
+func hashTag(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return fmt.Sprintf("%x", sum[:16])
+}
+
+// tags of single instances are split into the state of the instance, compared by
+// If-Match, and the representation of it, so changes can be made from any response
+const TAG_SEPARATOR = "-"
+
+func instanceETag(state string, representation string) string {
+	return fmt.Sprintf(`"%s%s%s"`, state, TAG_SEPARATOR, representation)
+}
+
+// identifies an instance by its version, however it is represented
+func versionState(m *schema.Model, id string, version interface{}) string {
+	if modified, ok := version.(time.Time); ok {
+		version = modified.UnixNano()
+	}
+	return hashTag(fmt.Sprintf("%s:%s:%v", m.Type, id, version))
+}
+
+// identifies an instance by its serialised values, or empty if it does not exist
+// loaded in full and past any cache, so sparse fieldsets and stale copies don't count
+func instanceState(c schema.Context, m *schema.Model, id string) string {
+	noInclude := schema.Include{
+		Children: map[string]*schema.Include{},
+	}
+	instances, _, err := c.GetObjectsByIDsAllRelations(m, []string{id}, &noInclude)
+	if err != nil {
+		panic(err)
+	}
+	if len(instances) == 0 {
+		return ""
+	}
+	encoded, err := encodeUnescaped(instances[0])
+	if err != nil {
+		panic(err)
+	}
+	return hashTag(string(encoded))
+}
+
+// identifies the included relations and sparse fieldsets requested
+func representationTag(r *http.Request) string {
+	params := r.URL.Query()
+	keys := []string{}
+	for key := range params {
+		if key == INCLUDE_ARG || strings.HasPrefix(key, FIELDS_PREFIX) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	parts := []string{}
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s", key, strings.Join(params[key], ",")))
+	}
+	return hashTag(strings.Join(parts, "&"))
+}
+
+// whether an entity tag header lists the tag, ignoring weakness
+func etagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// whether an entity tag header lists any tag of the instance state
+func stateMatches(header string, state string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.Trim(strings.TrimPrefix(strings.TrimSpace(candidate), "W/"), `"`)
+		if candidate == "*" || strings.SplitN(candidate, TAG_SEPARATOR, 2)[0] == state {
+			return true
+		}
+	}
+	return false
+}
+
+func notModified(r *http.Request, header http.Header) bool {
+	noneMatch := r.Header.Get("If-None-Match")
+	if len(noneMatch) > 0 {
+		return etagMatches(noneMatch, header.Get("ETag"))
+	}
+	modifiedSince, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	lastModified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.After(modifiedSince)
+}
+
+// the declared version of an instance, or nil if the model has none or it does not exist
+// locks the instance until the transaction ends if one is given
+func instanceVersion(c schema.Context, tx schema.Transaction, m *schema.Model, id string) interface{} {
+	if len(m.VersionColumn) == 0 {
+		return nil
+	}
+	query := fmt.Sprintf(
+		`select %s from %s where %s = $1`,
+		m.VersionColumn,
+		m.Table,
+		m.IDColumn,
+	)
+	var rows *sql.Rows
+	var err error
+	if tx != nil {
+		rows, err = tx.Query(query+" for update", id)
+	} else {
+		rows, err = c.Query(query, id)
+	}
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+	var version interface{}
+	for rows.Next() {
+		err = rows.Scan(&version)
+		if err != nil {
+			panic(err)
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		panic(err)
+	}
+	return version
+}
+
+// tags a single instance response by its declared version, or by its values
+// and the body once written
+func setVersionHeaders(c schema.Context, w http.ResponseWriter, m *schema.Model, instance schema.Instance) {
+	id := instance.GetID()
+	version := instanceVersion(c, nil, m, id)
+	if version == nil {
+		conditional, ok := w.(*conditionalResponseWriter)
+		if ok {
+			conditional.state = instanceState(c, m, id)
+		}
+		return
+	}
+	w.Header().Set("ETag", instanceETag(versionState(m, id, version), representationTag(c.GetRequest())))
+	if modified, ok := version.(time.Time); ok {
+		w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// the state of the instance, locked until the transaction ends
+// or empty if it no longer exists
+func lockedState(c schema.Context, tx schema.Transaction, m *schema.Model, id string) string {
+	if len(m.VersionColumn) > 0 {
+		version := instanceVersion(c, tx, m, id)
+		if version == nil {
+			return ""
+		}
+		return versionState(m, id, version)
+	}
+
+	_, err := tx.Exec(fmt.Sprintf(
+		`select 1 from %s where %s = $1 for update`,
+		m.Table,
+		m.IDColumn,
+	), id)
+	if err != nil {
+		panic(err)
+	}
+	return instanceState(c, m, id)
+}
+
+// rejects changes made against an outdated copy of the instance, compared within
+// the transaction making them so no other change can come between
+// returns false if the precondition failed and a response has been written
+func checkPreconditions(
+	c schema.Context,
+	w http.ResponseWriter,
+	tx schema.Transaction,
+	m *schema.Model,
+	id string,
+) bool {
+	ifMatch := c.GetRequest().Header.Get("If-Match")
+	if len(ifMatch) == 0 {
+		return true
+	}
+	state := lockedState(c, tx, m, id)
+	if len(state) > 0 && stateMatches(ifMatch, state) {
+		return true
+	}
+	PreconditionFailed(c, w)
+	return false
+}
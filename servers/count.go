@@ -0,0 +1,112 @@
+package servers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"strings"
+)
+
+// picks the count policy from the querystring, model then server
+func getCountPolicy(
+	c schema.Context,
+	m *schema.Model,
+	queryStrings map[string][]string,
+) (
+	string,
+	bool,
+	error,
+) {
+	policy, err := GetStringParam(queryStrings, utils.PAGE_COUNT, "Page Count", "")
+	if err != nil {
+		return "", false, err
+	}
+	if len(policy) > 0 {
+		if !schema.ValidCountPolicy(policy) {
+			return "", false, errors.New(fmt.Sprintf(
+				"Page count must be one of %s.",
+				strings.Join([]string{schema.COUNT_EXACT, schema.COUNT_NONE, schema.COUNT_ESTIMATE}, ", "),
+			))
+		}
+		return policy, true, nil
+	}
+	if len(m.CountPolicy) > 0 {
+		return m.CountPolicy, false, nil
+	}
+	if m.KeysetPagination {
+		return schema.COUNT_NONE, false, nil
+	}
+	return c.GetServer().GetDefaultCountPolicy(), false, nil
+}
+
+func exactCount(c schema.Context, m *schema.Model, whereClause string, whereArgs []interface{}) int {
+	query := fmt.Sprintf(
+		`
+			select
+				count(*)
+			from %s
+			%s
+		`,
+		m.Table,
+		whereClause,
+	)
+	var count int
+	err := c.QueryRow(query, whereArgs...).Scan(&count)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+type explainPlan struct {
+	Plan struct {
+		Rows float64 `json:"Plan Rows"`
+	}
+}
+
+// asks the planner how many rows it expects rather than counting them
+func estimateCount(c schema.Context, m *schema.Model, whereClause string, whereArgs []interface{}) int {
+	// unfiltered tables can use the statistics directly
+	if len(whereClause) == 0 {
+		var estimate float64
+		err := c.QueryRow(
+			`select reltuples from pg_class where oid = to_regclass($1)`,
+			m.Table,
+		).Scan(&estimate)
+		if err != nil {
+			panic(err)
+		}
+		// tables that have never been analysed report -1
+		if estimate >= 0 {
+			return int(estimate)
+		}
+	}
+
+	query := fmt.Sprintf(
+		`
+			explain (format json)
+			select
+				1
+			from %s
+			%s
+		`,
+		m.Table,
+		whereClause,
+	)
+	var planBlob string
+	err := c.QueryRow(query, whereArgs...).Scan(&planBlob)
+	if err != nil {
+		panic(err)
+	}
+	var plans []explainPlan
+	err = json.Unmarshal([]byte(planBlob), &plans)
+	if err != nil {
+		panic(err)
+	}
+	if len(plans) == 0 {
+		return 0
+	}
+	return int(plans[0].Plan.Rows)
+}
@@ -8,13 +8,23 @@ import (
 
 func DetailHandler(s schema.Server, m *schema.Model, w http.ResponseWriter, r *http.Request) {
 	rc := NewRequestContext(s, w, r)
+	w = rc.ResponseWriter
 
 	defer catchExceptions(rc, w)()
 
+	method, allowed := allowMethods(rc, w, m.AllowedMethods("GET", "PATCH", "PUT", "DELETE")...)
+	if !allowed {
+		return
+	}
+
 	err := rc.Authenticate()
 	if err != nil {
 		return
 	}
+	if !rateLimit(rc, w, m, method) {
+		return
+	}
+	defer snapshotReads(rc, method)()
 
 	// parse query strings
 	queryStrings := r.URL.Query()
@@ -22,7 +32,13 @@ func DetailHandler(s schema.Server, m *schema.Model, w http.ResponseWriter, r *h
 	// extract included information
 	include, err := parseInclude(rc, m, queryStrings)
 	if err != nil {
-		BadRequest(rc, w, "Bad Included Relations Parameter", err.Error())
+		BadParameter(rc, w, "Bad Included Relations Parameter", INCLUDE_ARG, err.Error())
+		return
+	}
+	// and sparse fieldsets
+	err = parseFields(rc, include, queryStrings)
+	if err != nil {
+		BadRequest(rc, w, "Bad Sparse Fieldset Parameter", err.Error())
 		return
 	}
 
@@ -31,12 +47,12 @@ func DetailHandler(s schema.Server, m *schema.Model, w http.ResponseWriter, r *h
 	id := vars["id"]
 
 	// handle request based on method
-	if r.Method == "PATCH" || r.Method == "PUT" {
+	if method == "PATCH" || method == "PUT" {
 		detailPATCH(w, r, rc, m, id, include)
-	} else if r.Method == "GET" {
+	} else if method == "GET" {
 		detailGET(w, r, rc, m, id, include)
-	} else {
-		MethodNotAllowed(rc, w)
+	} else if method == "DELETE" {
+		detailDELETE(w, r, rc, m, id)
 	}
 
 	rc.LogStats()
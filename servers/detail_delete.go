@@ -0,0 +1,120 @@
+package servers
+
+import (
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"net/http"
+)
+
+func detailDELETE(
+	w http.ResponseWriter,
+	r *http.Request,
+	c schema.Context,
+	m *schema.Model,
+	id string,
+) {
+	// get instance
+	noInclude := schema.Include{
+		Children: map[string]*schema.Include{},
+	}
+	instances, _, err := c.GetObjectsByIDsAllRelations(m, []string{id}, &noInclude)
+	if err != nil {
+		panic(err)
+	}
+	if len(instances) == 0 {
+		NotFound(c, w, m.Type, id)
+		return
+	}
+
+	// check the user has access to the instance
+	hasAccess := c.CanAccessAllInstances(instances)
+	if !hasAccess {
+		Forbidden(c, w, "Forbidden", "You do not have access to this object.")
+		return
+	}
+	err = authoriseDelete(c, m, instances[0])
+	if err != nil {
+		writeRequestError(c, w, err)
+		return
+	}
+
+	// start a transaction
+	tx, err := c.Begin()
+	if err != nil {
+		panic(err)
+	}
+	// which may require the client to hold the current version
+	if !checkPreconditions(c, w, tx, m, id) {
+		tx.Rollback()
+		return
+	}
+
+	// validate and delete the instance
+	valuesMap := instances[0].GetValues()
+	err = deleteInstance(c, tx, m, id, valuesMap)
+	if err != nil {
+		tx.Rollback()
+		writeRequestError(c, w, err)
+		return
+	}
+
+	// commit transaction
+	err = tx.Commit()
+	if err != nil {
+		panic(err)
+	}
+	c.GetServer().InvalidateCache(m.Type)
+	m.Manager.AfterDelete(c, id, valuesMap)
+
+	// flush instance cache
+	c.FlushCache()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validates against the manager and deletes the instance within the transaction
+func deleteInstance(
+	c schema.Context,
+	tx schema.Transaction,
+	m *schema.Model,
+	id string,
+	valuesMap map[string]interface{},
+) error {
+	// run manager validation
+	err := m.Manager.BeforeDelete(c, id, valuesMap)
+	if err != nil {
+		return validationRequestError(validationExceptions("Bad Instance Deletion", err, "/data"))
+	}
+
+	// clean up relations pointing at the instance
+	var queries []schema.Query
+	for _, relationship := range m.Relationships {
+		queries = append(queries, relationship.GetDeleteQueries(id)...)
+	}
+	for _, query := range queries {
+		_, err := tx.Exec(query.Query, query.Args...)
+		if err != nil {
+			tx.Rollback()
+			panic(err)
+		}
+	}
+
+	// delete the instance itself
+	query := fmt.Sprintf(
+		`delete from %s where %s = $1;`,
+		m.Table,
+		m.IDColumn,
+	)
+	_, err = tx.Exec(query, id)
+	if err != nil {
+		tx.Rollback()
+		panic(err)
+	}
+
+	// run manager transaction hook
+	err = m.Manager.DuringDelete(c, tx, id, valuesMap)
+	if err != nil {
+		return validationRequestError(validationExceptions("Bad Instance Deletion", err, "/data"))
+	}
+
+	return nil
+}
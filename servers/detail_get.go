@@ -29,19 +29,17 @@ func detailGET(
 		return
 	}
 
+	setVersionHeaders(c, w, m, instances[0])
+
 	responseBlob := struct {
 		Data     interface{} `json:"data"`
 		Included interface{} `json:"included,omitempty"`
 	}{
-		Data: instances[0],
+		Data: generalInstances(c, instances, include)[0],
 	}
 	if len(included) > 0 {
 		uniqueIncluded := UniqueInstances(included)
-		var generalIncluded []interface{}
-		for _, instance := range uniqueIncluded {
-			generalIncluded = append(generalIncluded, instance)
-		}
-		responseBlob.Included = generalIncluded
+		responseBlob.Included = generalInstances(c, uniqueIncluded, include)
 	}
 
 	c.WriteToResponse(responseBlob)
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
 	"github.com/davecgh/go-spew/spew"
 	"io/ioutil"
 	"net/http"
@@ -31,6 +32,11 @@ func detailPATCH(
 		return
 	}
 	instance := instances[0]
+	err = authoriseUpdate(c, m, instance)
+	if err != nil {
+		writeRequestError(c, w, err)
+		return
+	}
 
 	// read request data
 	body, err := ioutil.ReadAll(r.Body)
@@ -57,14 +63,51 @@ func detailPATCH(
 	// and updated values
 	updatesMap := updatedInstance.GetValues()
 	updates := valuesFromMap(updatesMap, m.Attributes, m.Relationships)
+	// check all values for validity
+	err = validateUpdates(c, m, instance, originals, updates)
+	if err != nil {
+		writeRequestError(c, w, err)
+		return
+	}
+
+	// write changes
+	saved := saveUpdates(w, c, m, id, originalsMap, originals, updates, true)
+	if !saved {
+		return
+	}
+
+	// return updated object as though it were a GET
+	detailGET(w, r, c, m, id, include)
+}
+
+// checks updated values against the originals, replacing them with the cleaned values
+func validateUpdates(
+	c schema.Context,
+	m *schema.Model,
+	instance schema.Instance,
+	originals []interface{},
+	updates []interface{},
+) error {
+	var err error
+	exceptions := []Exception{}
+
 	// check all attributes for validity
 	valueIndex := 0
 	for _, attribute := range m.Attributes {
 		if updates[valueIndex] != nil {
 			updates[valueIndex], err = attribute.ValidateUpdate(updates[valueIndex], originals[valueIndex])
+			if err == nil && updates[valueIndex] != nil {
+				err = checkUpdatable(attribute.GetAccess(), utils.AttributeError, attribute.GetKey())
+			}
+			if err == nil && updates[valueIndex] != nil {
+				err = checkWritable(c, m, instance, utils.AttributeError, attribute.GetKey())
+			}
 			if err != nil {
-				BadRequest(c, w, "Bad Attribute Value", err.Error())
-				return
+				exceptions = append(exceptions, validationExceptions(
+					"Bad Attribute Value",
+					err,
+					utils.ATTRIBUTES_POINTER+attribute.GetKey(),
+				)...)
 			}
 		}
 		valueIndex += 1
@@ -73,27 +116,124 @@ func detailPATCH(
 	for _, relation := range m.Relationships {
 		if updates[valueIndex] != nil {
 			updates[valueIndex], err = relation.ValidateUpdate(c, updates[valueIndex], originals[valueIndex])
+			if err == nil && updates[valueIndex] != nil {
+				err = checkUpdatable(relation.GetAccess(), utils.RelationshipError, relation.GetKey())
+			}
+			if err == nil && updates[valueIndex] != nil {
+				err = checkWritable(c, m, instance, utils.RelationshipError, relation.GetKey())
+			}
 			if err != nil {
-				BadRequest(c, w, "Bad Relationship Value", err.Error())
-				return
+				exceptions = append(exceptions, validationExceptions(
+					"Bad Relationship Value",
+					err,
+					utils.RELATIONSHIPS_POINTER+relation.GetKey(),
+				)...)
 			}
 		}
 		valueIndex += 1
 	}
+	return validationRequestError(exceptions)
+}
+
+// rejects changes to fields that cannot be updated
+func checkUpdatable(
+	access string,
+	fieldError func(string, string, string) utils.ValidationError,
+	key string,
+) error {
+	if access == schema.ACCESS_READ_ONLY {
+		return fieldError(key, utils.CODE_READ_ONLY, fmt.Sprintf("Field '%s' is read only.", key))
+	}
+	if access == schema.ACCESS_CREATE_ONLY {
+		return fieldError(key, utils.CODE_CREATE_ONLY, fmt.Sprintf("Field '%s' cannot be changed once created.", key))
+	}
+	return nil
+}
+
+// rejects values the user is not permitted to write
+func checkWritable(
+	c schema.Context,
+	m *schema.Model,
+	instance schema.Instance,
+	fieldError func(string, string, string) utils.ValidationError,
+	key string,
+) error {
+	if m.Manager.CanWriteField(c.GetUser(), instance, key) {
+		return nil
+	}
+	err := fieldError(key, utils.CODE_FORBIDDEN, fmt.Sprintf("You cannot change field '%s'.", key))
+	err.Status = http.StatusForbidden
+	return err
+}
 
+// validates against the manager and writes updates to the database
+// conditional updates may require the client to hold the current version
+// returns false if the updates were rejected and a response has been written
+func saveUpdates(
+	w http.ResponseWriter,
+	c schema.Context,
+	m *schema.Model,
+	id string,
+	originalsMap map[string]interface{},
+	originals []interface{},
+	updates []interface{},
+	conditional bool,
+) bool {
+	// start a transaction
+	tx, err := c.Begin()
+	if err != nil {
+		panic(err)
+	}
+	if conditional && !checkPreconditions(c, w, tx, m, id) {
+		tx.Rollback()
+		return false
+	}
+
+	updatesMap, err := writeUpdates(c, tx, m, id, originalsMap, originals, updates)
+	if err != nil {
+		tx.Rollback()
+		writeRequestError(c, w, err)
+		return false
+	}
+
+	// commit transaction
+	err = tx.Commit()
+	if err != nil {
+		panic(err)
+	}
+	c.GetServer().InvalidateCache(m.Type)
+	m.Manager.AfterUpdate(c, id, originalsMap, updatesMap)
+
+	// flush instance cache
+	c.FlushCache()
+	return true
+}
+
+// validates against the manager and writes updates within the transaction
+// returns the updated values
+func writeUpdates(
+	c schema.Context,
+	tx schema.Transaction,
+	m *schema.Model,
+	id string,
+	originalsMap map[string]interface{},
+	originals []interface{},
+	updates []interface{},
+) (
+	map[string]interface{},
+	error,
+) {
 	// run manager validation
-	updatesMap = mapFromValues(updates, m.Attributes, m.Relationships)
+	updatesMap := mapFromValues(updates, m.Attributes, m.Relationships)
 	// instance = m.Manager.Create()
 	// instance.SetValues(mapValues)
-	err = m.Manager.BeforeUpdate(c, originalsMap, updatesMap)
+	err := m.Manager.BeforeUpdate(c, originalsMap, updatesMap)
 	if err != nil {
-		BadRequest(c, w, "Bad Instance Update", err.Error())
-		return
+		return nil, validationRequestError(validationExceptions("Bad Instance Update", err, "/data"))
 	}
 	err = m.Manager.BeforeSave(c, updatesMap)
 	if err != nil {
-		BadRequest(c, w, "Bad Instance", err.Error())
-		return
+		return nil, validationRequestError(validationExceptions("Bad Instance", err, "/data"))
 	}
 
 	// build update query
@@ -101,7 +241,7 @@ func detailPATCH(
 	var updateKeys []string
 	var updateArgs []interface{}
 
-	valueIndex = 0
+	valueIndex := 0
 	for _, attribute := range m.Attributes {
 		// skip nil values (use database default)
 		value := updates[valueIndex]
@@ -129,12 +269,6 @@ func detailPATCH(
 	}
 	spew.Dump(updates)
 
-	// start a transaction
-	tx, err := c.Begin()
-	if err != nil {
-		panic(err)
-	}
-
 	updateQueries := []schema.Query{}
 	valueIndex = len(m.Attributes)
 	for _, relation := range m.Relationships {
@@ -147,7 +281,11 @@ func detailPATCH(
 	}
 
 	for _, query := range updateQueries {
-		tx.Exec(query.Query, query.Args...)
+		_, err = tx.Exec(query.Query, query.Args...)
+		if err != nil {
+			tx.Rollback()
+			panic(err)
+		}
 	}
 
 	if len(updateKeys) > 0 {
@@ -172,14 +310,11 @@ func detailPATCH(
 		}
 	}
 
-	// commit transaction
-	err = tx.Commit()
+	// run manager transaction hook
+	err = m.Manager.DuringUpdate(c, tx, id, originalsMap, updatesMap)
 	if err != nil {
-		panic(err)
+		return nil, validationRequestError(validationExceptions("Bad Instance Update", err, "/data"))
 	}
 
-	// flush instance cache
-	c.FlushCache()
-	// return updated object as though it were a GET
-	detailGET(w, r, c, m, id, include)
+	return updatesMap, nil
 }
@@ -1,97 +1,246 @@
 package servers
 
 import (
+	"context"
 	"fmt"
 	"github.com/bor3ham/reja/schema"
-	"net/http"
+	"github.com/bor3ham/reja/utils"
 	"log"
+	"net/http"
+	"strconv"
+	"strings"
 )
 
 type Error struct {
 	Exceptions []Exception `json:"errors"`
 }
 type Exception struct {
-	Title  string `json:"title"`
-	Detail string `json:"detail"`
+	Status string           `json:"status,omitempty"`
+	Code   string           `json:"code,omitempty"`
+	Title  string           `json:"title"`
+	Detail string           `json:"detail"`
+	Source *ExceptionSource `json:"source,omitempty"`
+}
+type ExceptionSource struct {
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
 }
 
-func BadRequest(c schema.Context, w http.ResponseWriter, title string, detail string) {
+func writeExceptions(c schema.Context, w http.ResponseWriter, status int, exceptions []Exception) {
 	errorBlob := Error{
-		Exceptions: []Exception{
-			Exception{
-				Title:  title,
-				Detail: detail,
-			},
-		},
+		Exceptions: exceptions,
 	}
-	w.WriteHeader(http.StatusBadRequest)
+	w.WriteHeader(status)
 	c.WriteToResponse(errorBlob)
 }
 
-func Forbidden(c schema.Context, w http.ResponseWriter, title string, detail string) {
-	errorBlob := Error{
-		Exceptions: []Exception{
-			Exception{
-				Title:  title,
-				Detail: detail,
+func BadRequest(c schema.Context, w http.ResponseWriter, title string, detail string) {
+	writeExceptions(c, w, http.StatusBadRequest, []Exception{
+		Exception{
+			Status: strconv.Itoa(http.StatusBadRequest),
+			Title:  title,
+			Detail: detail,
+		},
+	})
+}
+
+func BadParameter(c schema.Context, w http.ResponseWriter, title string, parameter string, detail string) {
+	writeExceptions(c, w, http.StatusBadRequest, []Exception{
+		Exception{
+			Status: strconv.Itoa(http.StatusBadRequest),
+			Code:   utils.CODE_INVALID,
+			Title:  title,
+			Detail: detail,
+			Source: &ExceptionSource{
+				Parameter: parameter,
 			},
 		},
-	}
-	w.WriteHeader(http.StatusForbidden)
-	c.WriteToResponse(errorBlob)
+	})
+}
+
+func Forbidden(c schema.Context, w http.ResponseWriter, title string, detail string) {
+	writeExceptions(c, w, http.StatusForbidden, []Exception{
+		Exception{
+			Status: strconv.Itoa(http.StatusForbidden),
+			Title:  title,
+			Detail: detail,
+		},
+	})
 }
 
 func NotFound(c schema.Context, w http.ResponseWriter, model string, id string) {
-	errorBlob := Error{
-		Exceptions: []Exception{
-			Exception{
-				Title:  "Not Found",
-				Detail: fmt.Sprintf(
-					"No %s found with ID '%s'.",
-					model,
-					id,
-				),
-			},
+	writeExceptions(c, w, http.StatusNotFound, []Exception{
+		Exception{
+			Status: strconv.Itoa(http.StatusNotFound),
+			Title:  "Not Found",
+			Detail: fmt.Sprintf(
+				"No %s found with ID '%s'.",
+				model,
+				id,
+			),
 		},
-	}
-	w.WriteHeader(http.StatusNotFound)
-	c.WriteToResponse(errorBlob)
+	})
 }
 
-func MethodNotAllowed(c schema.Context, w http.ResponseWriter) {
-	errorBlob := Error{
-		Exceptions: []Exception{
-			Exception{
-				Title: "Method Not Allowed",
-				Detail: fmt.Sprintf(
-					"This endpoint does not support %s requests.",
-					c.GetRequest().Method,
-				),
-			},
+func MethodNotAllowed(c schema.Context, w http.ResponseWriter, methods []string) {
+	w.Header().Set("Allow", allowHeader(methods))
+	writeExceptions(c, w, http.StatusMethodNotAllowed, []Exception{
+		Exception{
+			Status: strconv.Itoa(http.StatusMethodNotAllowed),
+			Title:  "Method Not Allowed",
+			Detail: fmt.Sprintf(
+				"This endpoint does not support %s requests.",
+				c.GetRequest().Method,
+			),
 		},
-	}
-	w.WriteHeader(http.StatusForbidden)
-	c.WriteToResponse(errorBlob)
+	})
+}
+
+func PreconditionFailed(c schema.Context, w http.ResponseWriter) {
+	writeExceptions(c, w, http.StatusPreconditionFailed, []Exception{
+		Exception{
+			Status: strconv.Itoa(http.StatusPreconditionFailed),
+			Title:  "Precondition Failed",
+			Detail: "The object has changed since it was last retrieved.",
+		},
+	})
 }
 
 func InternalServerError(c schema.Context, w http.ResponseWriter) {
-	errorBlob := Error{
+	writeExceptions(c, w, http.StatusInternalServerError, []Exception{
+		Exception{
+			Status: strconv.Itoa(http.StatusInternalServerError),
+			Title:  "Internal Server Error",
+			Detail: "Something went wrong. Please try again later.",
+		},
+	})
+}
+
+// a client error raised while writing, to be reported once the transaction is abandoned
+type RequestError struct {
+	Status     int
+	Exceptions []Exception
+}
+
+func (e RequestError) Error() string {
+	details := []string{}
+	for _, exception := range e.Exceptions {
+		details = append(details, exception.Detail)
+	}
+	return strings.Join(details, " ")
+}
+
+func badRequestError(title string, detail string) RequestError {
+	return RequestError{
+		Status: http.StatusBadRequest,
 		Exceptions: []Exception{
 			Exception{
-				Title: "Internal Server Error",
-				Detail: "Something went wrong. Please try again later.",
+				Status: strconv.Itoa(http.StatusBadRequest),
+				Title:  title,
+				Detail: detail,
 			},
 		},
 	}
-	w.WriteHeader(http.StatusInternalServerError)
-	c.WriteToResponse(errorBlob)
+}
+
+// describes each validation problem in an error, pointing at the given location if unknown
+func validationExceptions(title string, err error, pointer string) []Exception {
+	exceptions := []Exception{}
+	for _, validationError := range utils.AsValidationErrors(err, pointer) {
+		exception := Exception{
+			Status: strconv.Itoa(validationError.Status),
+			Code:   validationError.Code,
+			Title:  title,
+			Detail: validationError.Error(),
+		}
+		if len(validationError.Pointer) > 0 || len(validationError.Parameter) > 0 {
+			exception.Source = &ExceptionSource{
+				Pointer:   validationError.Pointer,
+				Parameter: validationError.Parameter,
+			}
+		}
+		exceptions = append(exceptions, exception)
+	}
+	return exceptions
+}
+
+// a request error made of validation exceptions, or nil if there are none
+// mixed statuses are reported as a bad request
+func validationRequestError(exceptions []Exception) error {
+	if len(exceptions) == 0 {
+		return nil
+	}
+	status := exceptions[0].Status
+	for _, exception := range exceptions {
+		if exception.Status != status {
+			status = strconv.Itoa(http.StatusBadRequest)
+			break
+		}
+	}
+	statusCode, err := strconv.Atoi(status)
+	if err != nil {
+		statusCode = http.StatusBadRequest
+	}
+	return RequestError{
+		Status:     statusCode,
+		Exceptions: exceptions,
+	}
+}
+
+// writes a request error to the response, any other error is unexpected
+func writeRequestError(c schema.Context, w http.ResponseWriter, err error) {
+	requestError, ok := err.(RequestError)
+	if !ok {
+		panic(err)
+	}
+	writeExceptions(c, w, requestError.Status, requestError.Exceptions)
+}
+
+func GatewayTimeout(c schema.Context, w http.ResponseWriter) {
+	writeExceptions(c, w, http.StatusGatewayTimeout, []Exception{
+		Exception{
+			Status: strconv.Itoa(http.StatusGatewayTimeout),
+			Title:  "Gateway Timeout",
+			Detail: "The request took too long to complete.",
+		},
+	})
+}
+
+func ServiceUnavailable(c schema.Context, w http.ResponseWriter) {
+	writeExceptions(c, w, http.StatusServiceUnavailable, []Exception{
+		Exception{
+			Status: strconv.Itoa(http.StatusServiceUnavailable),
+			Title:  "Service Unavailable",
+			Detail: "The request was cancelled before it could complete.",
+		},
+	})
 }
 
 func catchExceptions(c schema.Context, w http.ResponseWriter) func() {
 	return func() {
+		// cancelled statements fail with driver errors, so check the request context itself
+		requestErr := c.GetContext().Err()
+		finisher, ok := c.(interface {
+			Finish()
+		})
+		if ok {
+			defer finisher.Finish()
+		}
 		if err := recover(); err != nil {
-			InternalServerError(c, w)
-			log.Printf("Runtime panic: %v", err)
+			conditional, ok := w.(*conditionalResponseWriter)
+			if ok {
+				conditional.reset()
+			}
+			if requestErr == context.DeadlineExceeded {
+				GatewayTimeout(c, w)
+				log.Printf("Request timed out: %v", err)
+			} else if requestErr == context.Canceled {
+				ServiceUnavailable(c, w)
+				log.Printf("Request cancelled: %v", err)
+			} else {
+				InternalServerError(c, w)
+				log.Printf("Runtime panic: %v", err)
+			}
 		}
 	}
 }
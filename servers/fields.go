@@ -0,0 +1,191 @@
+package servers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"strings"
+)
+
+const FIELDS_PREFIX = "fields["
+const FIELDS_SUFFIX = "]"
+
+func validateFields(model *schema.Model, fields []string) error {
+	for _, field := range fields {
+		if !schema.Readable(model.FieldAccess(field)) {
+			return errors.New(fmt.Sprintf("Field %s cannot be read on model %s", field, model.Type))
+		}
+		found := false
+		for _, attribute := range model.Attributes {
+			if attribute.GetKey() == field {
+				found = true
+			}
+		}
+		for _, relationship := range model.Relationships {
+			if relationship.GetKey() == field {
+				found = true
+			}
+		}
+		if !found {
+			return errors.New(fmt.Sprintf("Field %s not found on model %s", field, model.Type))
+		}
+	}
+	return nil
+}
+
+func parseFields(
+	c schema.Context,
+	include *schema.Include,
+	params map[string][]string,
+) error {
+	fields := map[string][]string{}
+	for key, _ := range params {
+		if !strings.HasPrefix(key, FIELDS_PREFIX) || !strings.HasSuffix(key, FIELDS_SUFFIX) {
+			continue
+		}
+		modelType := strings.TrimSuffix(strings.TrimPrefix(key, FIELDS_PREFIX), FIELDS_SUFFIX)
+
+		// extract from querystring
+		fieldsString, err := GetStringParam(
+			params,
+			key,
+			"Sparse Fieldset",
+			"",
+		)
+		if err != nil {
+			return err
+		}
+
+		// split out of querystring into list
+		modelFields := []string{}
+		for _, field := range strings.Split(fieldsString, ",") {
+			field = strings.TrimSpace(field)
+			if len(field) == 0 {
+				continue
+			}
+			modelFields = append(modelFields, field)
+		}
+
+		// validate the list
+		model := c.GetServer().GetModel(modelType)
+		if model == nil {
+			return errors.New(fmt.Sprintf("Model %s not found", modelType))
+		}
+		err = validateFields(model, modelFields)
+		if err != nil {
+			return err
+		}
+		fields[modelType] = modelFields
+	}
+
+	if len(fields) > 0 {
+		include.SetFields(fields)
+	}
+	return nil
+}
+
+// an instance with the attributes and relationships outside its fieldset removed
+type SparseInstance struct {
+	Instance schema.Instance
+	Fields   []string
+}
+
+func encodeUnescaped(blob interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(blob)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buffer.Bytes()), nil
+}
+
+func (si SparseInstance) MarshalJSON() ([]byte, error) {
+	full, err := encodeUnescaped(si.Instance)
+	if err != nil {
+		return nil, err
+	}
+	var document map[string]json.RawMessage
+	err = json.Unmarshal(full, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, member := range []string{"attributes", "relationships"} {
+		values, exists := document[member]
+		if !exists {
+			continue
+		}
+		var valueMap map[string]json.RawMessage
+		err = json.Unmarshal(values, &valueMap)
+		if err != nil {
+			return nil, err
+		}
+		sparseMap := map[string]json.RawMessage{}
+		for _, field := range si.Fields {
+			value, exists := valueMap[field]
+			if exists {
+				sparseMap[field] = value
+			}
+		}
+		sparseValues, err := encodeUnescaped(sparseMap)
+		if err != nil {
+			return nil, err
+		}
+		document[member] = sparseValues
+	}
+
+	return encodeUnescaped(document)
+}
+
+// generalises instances for a response, applying any sparse fieldsets
+// and removing fields that cannot be read
+func generalInstances(
+	c schema.Context,
+	instances []schema.Instance,
+	include *schema.Include,
+) []interface{} {
+	general := []interface{}{}
+	for _, instance := range instances {
+		instanceType := instance.GetType()
+		model := c.GetServer().GetModel(instanceType)
+		if model == nil {
+			general = append(general, instance)
+			continue
+		}
+
+		candidates := model.ReadableFields()
+		if include.Restricts(instanceType) {
+			candidates = include.Fields[instanceType]
+		}
+		fields := visibleFields(c, model, instance, candidates)
+
+		if include.Restricts(instanceType) || len(fields) < len(model.Attributes)+len(model.Relationships) {
+			general = append(general, SparseInstance{
+				Instance: instance,
+				Fields:   fields,
+			})
+		} else {
+			general = append(general, instance)
+		}
+	}
+	return general
+}
+
+// the fields of an instance the current user may see
+func visibleFields(c schema.Context, m *schema.Model, instance schema.Instance, candidates []string) []string {
+	fields := []string{}
+	for _, field := range candidates {
+		if !schema.Readable(m.FieldAccess(field)) {
+			continue
+		}
+		if !m.Manager.CanReadField(c.GetUser(), instance, field) {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
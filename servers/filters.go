@@ -0,0 +1,206 @@
+package servers
+
+import (
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"sort"
+	"strings"
+)
+
+// validates the filters on a model, including those through its relationships,
+// collecting every problem
+func validateFilters(
+	c schema.Context,
+	m *schema.Model,
+	queries map[string][]string,
+) (
+	[]schema.Filter,
+	[]Exception,
+) {
+	var validFilters []schema.Filter
+	exceptions := []Exception{}
+	for _, attribute := range m.Attributes {
+		// filtering would reveal write only or hidden values
+		if !m.FieldVisible(c.GetUser(), attribute.GetKey()) {
+			continue
+		}
+		filters, err := attribute.ValidateFilters(queries)
+		if err != nil {
+			exceptions = append(exceptions, validationExceptions("Bad Filter Parameter", err, "")...)
+		}
+		validFilters = append(validFilters, filters...)
+	}
+	for _, relationship := range m.Relationships {
+		if !m.FieldVisible(c.GetUser(), relationship.GetKey()) {
+			continue
+		}
+		filters, err := relationship.ValidateFilters(queries)
+		if err != nil {
+			exceptions = append(exceptions, validationExceptions("Bad Filter Parameter", err, "")...)
+		}
+		validFilters = append(validFilters, filters...)
+	}
+	filters, relatedExceptions := validateRelatedFilters(c, m, queries)
+	validFilters = append(validFilters, filters...)
+	exceptions = append(exceptions, relatedExceptions...)
+	return validFilters, exceptions
+}
+
+func filterException(parameter string, text string, args ...interface{}) []Exception {
+	return validationExceptions("Bad Filter Parameter", utils.ParameterError(
+		parameter,
+		utils.CODE_INVALID_FILTER,
+		fmt.Sprintf(text, args...),
+	), "")
+}
+
+// validates filters on related models such as author.name__contains=smith,
+// walking the relationships like validateInclude
+func validateRelatedFilters(
+	c schema.Context,
+	m *schema.Model,
+	queries map[string][]string,
+) (
+	[]schema.Filter,
+	[]Exception,
+) {
+	validFilters := []schema.Filter{}
+	exceptions := []Exception{}
+
+	// group the filters by the relationship they pass through
+	relatedQueries := map[string]map[string][]string{}
+	for key, values := range queries {
+		parts := strings.SplitN(key, ".", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		_, exists := relatedQueries[parts[0]]
+		if !exists {
+			relatedQueries[parts[0]] = map[string][]string{}
+		}
+		relatedQueries[parts[0]][parts[1]] = values
+	}
+	relationKeys := []string{}
+	for key := range relatedQueries {
+		relationKeys = append(relationKeys, key)
+	}
+	sort.Strings(relationKeys)
+
+	for _, key := range relationKeys {
+		var relation schema.Relationship
+		for _, modelRelation := range m.Relationships {
+			if modelRelation.GetKey() == key {
+				relation = modelRelation
+			}
+		}
+		// other parameters may contain dots
+		if relation == nil {
+			continue
+		}
+		prefix := key + "."
+		if !m.FieldVisible(c.GetUser(), key) {
+			exceptions = append(exceptions, filterException(
+				prefix,
+				"Relation '%s' cannot be read on model '%s'.",
+				key,
+				m.Type,
+			)...)
+			continue
+		}
+		through, ok := relation.(schema.FilteringRelationship)
+		var related *schema.Model
+		if ok && len(through.GetFilterType()) > 0 {
+			related = c.GetServer().GetModel(through.GetFilterType())
+		}
+		if related == nil {
+			exceptions = append(exceptions, filterException(
+				prefix,
+				"Cannot filter through relation '%s' on model '%s'.",
+				key,
+				m.Type,
+			)...)
+			continue
+		}
+
+		filters, relatedExceptions := validateFilters(c, related, relatedQueries[key])
+		for _, exception := range relatedExceptions {
+			if exception.Source != nil && len(exception.Source.Parameter) > 0 {
+				source := *exception.Source
+				source.Parameter = prefix + source.Parameter
+				exception.Source = &source
+			}
+			exceptions = append(exceptions, exception)
+		}
+		// the relation was named explicitly, so unknown filters are not ignored
+		matched := map[string]bool{}
+		for _, filter := range filters {
+			matched[filter.GetQArgKey()] = true
+		}
+		for relatedKey := range relatedQueries[key] {
+			if !matched[relatedKey] && len(relatedExceptions) == 0 {
+				exceptions = append(exceptions, filterException(
+					prefix+relatedKey,
+					"Unknown filter '%s' on model '%s'.",
+					relatedKey,
+					related.Type,
+				)...)
+			}
+		}
+
+		for _, filter := range filters {
+			validFilters = append(validFilters, relatedFilter{
+				Filter:   filter,
+				prefix:   prefix,
+				relation: through,
+				related:  related,
+			})
+		}
+	}
+	return validFilters, exceptions
+}
+
+// matches instances with any related instance passing the filter
+// each filter through a to-many relation may be passed by a different instance
+type relatedFilter struct {
+	schema.Filter
+	prefix   string
+	relation schema.FilteringRelationship
+	related  *schema.Model
+}
+
+func (f relatedFilter) GetQArgKey() string {
+	return f.prefix + f.Filter.GetQArgKey()
+}
+
+func (f relatedFilter) GetWhere(
+	c schema.Context,
+	modelTable string,
+	idColumn string,
+	nextArg int,
+) (
+	[]string,
+	[]interface{},
+) {
+	// a relation to the same table needs an alias to refer to the owning row
+	relatedTable := f.related.Table
+	from := f.related.Table
+	if relatedTable == modelTable {
+		relatedTable = "filtered_" + f.related.Table
+		from = fmt.Sprintf("%s as %s", f.related.Table, relatedTable)
+	}
+
+	conditions := []string{
+		f.relation.GetFilterCondition(modelTable, idColumn, relatedTable, f.related.IDColumn),
+	}
+	queries, args := f.Filter.GetWhere(c, relatedTable, f.related.IDColumn, nextArg)
+	conditions = append(conditions, queries...)
+	// instances hidden from the user cannot be matched
+	authQueries, authArgs := f.related.Manager.GetFilterForUser(c.GetUser(), nextArg+len(args))
+	conditions = append(conditions, authQueries...)
+	args = append(args, authArgs...)
+
+	return []string{
+		fmt.Sprintf("exists (select 1 from %s where %s)", from, strings.Join(conditions, " and ")),
+	}, args
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 )
 
+const INCLUDE_ARG = "include"
+
 func validateInclude(c schema.Context, model *schema.Model, include *schema.Include) error {
 	// its valid without children
 	if len(include.Children) == 0 {
@@ -27,6 +29,9 @@ func validateInclude(c schema.Context, model *schema.Model, include *schema.Incl
 		if relation == nil {
 			return errors.New(fmt.Sprintf("Relation %s not found on model %s", key, model.Type))
 		}
+		if !model.FieldVisible(c.GetUser(), key) {
+			return errors.New(fmt.Sprintf("Relation %s cannot be read on model %s", key, model.Type))
+		}
 		// recurse on its children
 		childModel := c.GetServer().GetModel(relation.GetType())
 		err := validateInclude(c, childModel, child)
@@ -48,7 +53,7 @@ func parseInclude(
 	// extract from querystring
 	includeString, err := GetStringParam(
 		params,
-		"include",
+		INCLUDE_ARG,
 		"Included Relations",
 		"",
 	)
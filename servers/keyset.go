@@ -0,0 +1,226 @@
+package servers
+
+import (
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"strings"
+)
+
+// ensures the ordering is unique by falling back to the id
+func keysetOrder(m *schema.Model, order []schema.OrderColumn) []schema.OrderColumn {
+	for _, column := range order {
+		if column.Column == m.IDColumn {
+			return order
+		}
+	}
+	return append(order, schema.OrderColumn{
+		Column: m.IDColumn,
+	})
+}
+
+func reversedOrder(order []schema.OrderColumn) []schema.OrderColumn {
+	reversed := []schema.OrderColumn{}
+	for _, column := range order {
+		reversed = append(reversed, schema.OrderColumn{
+			Column:     column.Column,
+			Descending: !column.Descending,
+		})
+	}
+	return reversed
+}
+
+// whether a column comes after the value in the ordering (nulls sort last ascending)
+func keysetLater(
+	column schema.OrderColumn,
+	value *string,
+	nextArg int,
+) (
+	string,
+	[]interface{},
+) {
+	if value == nil {
+		if column.Descending {
+			return fmt.Sprintf("%s is not null", column.Column), []interface{}{}
+		}
+		return "false", []interface{}{}
+	}
+	if column.Descending {
+		return fmt.Sprintf("%s < $%d", column.Column, nextArg), []interface{}{*value}
+	}
+	return fmt.Sprintf("(%s > $%d or %s is null)", column.Column, nextArg, column.Column),
+		[]interface{}{*value}
+}
+
+func keysetEqual(
+	column schema.OrderColumn,
+	value *string,
+	nextArg int,
+) (
+	string,
+	[]interface{},
+) {
+	if value == nil {
+		return fmt.Sprintf("%s is null", column.Column), []interface{}{}
+	}
+	return fmt.Sprintf("%s = $%d", column.Column, nextArg), []interface{}{*value}
+}
+
+// builds a where clause for rows after the cursor values in the given order
+func keysetWhere(
+	order []schema.OrderColumn,
+	values []*string,
+	nextArg int,
+) (
+	string,
+	[]interface{},
+) {
+	alternatives := []string{}
+	args := []interface{}{}
+	for index, column := range order {
+		conditions := []string{}
+		for equalIndex := 0; equalIndex < index; equalIndex++ {
+			condition, conditionArgs := keysetEqual(order[equalIndex], values[equalIndex], nextArg)
+			conditions = append(conditions, condition)
+			args = append(args, conditionArgs...)
+			nextArg += len(conditionArgs)
+		}
+		condition, conditionArgs := keysetLater(column, values[index], nextArg)
+		conditions = append(conditions, condition)
+		args = append(args, conditionArgs...)
+		nextArg += len(conditionArgs)
+
+		alternatives = append(alternatives, "("+strings.Join(conditions, " and ")+")")
+	}
+	return "(" + strings.Join(alternatives, " or ") + ")", args
+}
+
+// fetches a page of instances either side of a cursor
+// also returns cursors for the adjacent pages if they exist
+func getKeysetPage(
+	c schema.Context,
+	m *schema.Model,
+	whereQueries []string,
+	whereArgs []interface{},
+	order []schema.OrderColumn,
+	orderArgs []interface{},
+	cursor []*string,
+	before bool,
+	pageSize int,
+	include *schema.Include,
+) (
+	[]schema.Instance,
+	[]schema.Instance,
+	*string,
+	*string,
+) {
+	queryOrder := order
+	if before {
+		queryOrder = reversedOrder(order)
+	}
+
+	queries := append([]string{}, whereQueries...)
+	args := append([]interface{}{}, whereArgs...)
+	args = append(args, orderArgs...)
+	if cursor != nil {
+		cursorQuery, cursorArgs := keysetWhere(queryOrder, cursor, len(args)+1)
+		queries = append(queries, cursorQuery)
+		args = append(args, cursorArgs...)
+	}
+	whereClause := ""
+	if len(queries) > 0 {
+		whereClause = fmt.Sprintf("where %s", strings.Join(queries, " and "))
+	}
+
+	// find the ids and cursor values of the page, plus one to detect more
+	columns := []string{m.IDColumn}
+	for _, column := range queryOrder {
+		columns = append(columns, fmt.Sprintf("(%s)::text", column.Column))
+	}
+	query := fmt.Sprintf(
+		`
+			select
+				%s
+			from %s
+			%s
+			%s
+			limit %d
+		`,
+		strings.Join(columns, ", "),
+		m.Table,
+		whereClause,
+		schema.OrderQuery(queryOrder),
+		pageSize+1,
+	)
+	rows, err := c.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+	ids := []string{}
+	cursors := [][]*string{}
+	for rows.Next() {
+		var id string
+		values := make([]*string, len(queryOrder))
+		scanFields := []interface{}{&id}
+		for index, _ := range values {
+			scanFields = append(scanFields, &values[index])
+		}
+		err := rows.Scan(scanFields...)
+		if err != nil {
+			panic(err)
+		}
+		ids = append(ids, id)
+		cursors = append(cursors, values)
+	}
+	err = rows.Err()
+	if err != nil {
+		panic(err)
+	}
+
+	more := len(ids) > pageSize
+	if more {
+		ids = ids[:pageSize]
+		cursors = cursors[:pageSize]
+	}
+	// put the page back in order if it was read backwards
+	if before {
+		for left, right := 0, len(ids)-1; left < right; left, right = left+1, right-1 {
+			ids[left], ids[right] = ids[right], ids[left]
+			cursors[left], cursors[right] = cursors[right], cursors[left]
+		}
+	}
+
+	var prevCursor, nextCursor *string
+	if len(ids) > 0 {
+		if (before && more) || (!before && cursor != nil) {
+			encoded := utils.EncodeCursor(cursors[0])
+			prevCursor = &encoded
+		}
+		if (!before && more) || (before && cursor != nil) {
+			encoded := utils.EncodeCursor(cursors[len(cursors)-1])
+			nextCursor = &encoded
+		}
+	}
+	if len(ids) == 0 {
+		return []schema.Instance{}, []schema.Instance{}, prevCursor, nextCursor
+	}
+
+	// fetch the instances and restore the page order
+	instances, included, err := c.GetObjectsByIDs(m, ids, include)
+	if err != nil {
+		panic(err)
+	}
+	byID := map[string]schema.Instance{}
+	for _, instance := range instances {
+		byID[instance.GetID()] = instance
+	}
+	ordered := []schema.Instance{}
+	for _, id := range ids {
+		instance, exists := byID[id]
+		if exists {
+			ordered = append(ordered, instance)
+		}
+	}
+	return ordered, included, prevCursor, nextCursor
+}
@@ -7,11 +7,20 @@ import (
 
 func ListHandler(s schema.Server, m *schema.Model, w http.ResponseWriter, r *http.Request) {
 	rc := NewRequestContext(s, w, r)
+	w = rc.ResponseWriter
 	defer catchExceptions(rc, w)()
+	method, allowed := allowMethods(rc, w, m.AllowedMethods("GET", "POST")...)
+	if !allowed {
+		return
+	}
 	err := rc.Authenticate()
 	if err != nil {
 		return
 	}
+	if !rateLimit(rc, w, m, method) {
+		return
+	}
+	defer snapshotReads(rc, method)()
 
 	// parse query strings
 	queryStrings := r.URL.Query()
@@ -19,17 +28,21 @@ func ListHandler(s schema.Server, m *schema.Model, w http.ResponseWriter, r *htt
 	// extract included information
 	include, err := parseInclude(rc, m, queryStrings)
 	if err != nil {
-		BadRequest(rc, w, "Bad Included Relations Parameter", err.Error())
+		BadParameter(rc, w, "Bad Included Relations Parameter", INCLUDE_ARG, err.Error())
+		return
+	}
+	// and sparse fieldsets
+	err = parseFields(rc, include, queryStrings)
+	if err != nil {
+		BadRequest(rc, w, "Bad Sparse Fieldset Parameter", err.Error())
 		return
 	}
 
 	// handle request based on method
-	if r.Method == "POST" {
+	if method == "POST" {
 		listPOST(w, r, rc, m, queryStrings, include)
-	} else if r.Method == "GET" {
+	} else if method == "GET" {
 		listGET(w, r, rc, m, queryStrings, include)
-	} else {
-		MethodNotAllowed(rc, w)
 	}
 
 	rc.LogStats()
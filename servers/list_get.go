@@ -1,6 +1,7 @@
 package servers
 
 import (
+	"errors"
 	"fmt"
 	"github.com/bor3ham/reja/schema"
 	"github.com/bor3ham/reja/utils"
@@ -18,7 +19,21 @@ func listGET(
 	queryStrings map[string][]string,
 	include *schema.Include,
 ) {
-	// todo: condense into helper method (same as relation.go)
+	scopedListGET(w, r, c, m, queryStrings, include, []string{}, []interface{}{})
+}
+
+// lists instances of a model limited to those matching the scope queries
+func scopedListGET(
+	w http.ResponseWriter,
+	r *http.Request,
+	c schema.Context,
+	m *schema.Model,
+	queryStrings map[string][]string,
+	include *schema.Include,
+	scopeQueries []string,
+	scopeArgs []interface{},
+) {
+	// todo: condense into helper method (same as relation_get.go)
 	minPageSize := 1
 	maxPageSize := c.GetServer().GetMaximumDirectPageSize()
 	pageSize, err := GetIntParam(
@@ -30,7 +45,7 @@ func listGET(
 		&maxPageSize,
 	)
 	if err != nil {
-		BadRequest(c, w, "Bad Page Size Parameter", err.Error())
+		BadParameter(c, w, "Bad Page Size Parameter", utils.PAGE_SIZE, err.Error())
 		return
 	}
 	minPageOffset := 1
@@ -43,33 +58,52 @@ func listGET(
 		nil,
 	)
 	if err != nil {
-		BadRequest(c, w, "Bad Page Offset Parameter", err.Error())
+		BadParameter(c, w, "Bad Page Offset Parameter", utils.PAGE_OFFSET, err.Error())
 		return
 	}
 	offset := (pageOffset - 1) * pageSize
+	// or cursors
+	pageAfter, err := GetStringParam(queryStrings, utils.PAGE_AFTER, "Page After", "")
+	if err != nil {
+		BadParameter(c, w, "Bad Page Cursor Parameter", utils.PAGE_AFTER, err.Error())
+		return
+	}
+	pageBefore, err := GetStringParam(queryStrings, utils.PAGE_BEFORE, "Page Before", "")
+	if err != nil {
+		BadParameter(c, w, "Bad Page Cursor Parameter", utils.PAGE_BEFORE, err.Error())
+		return
+	}
+	keyset := m.KeysetPagination || len(pageAfter) > 0 || len(pageBefore) > 0
+	if len(pageAfter) > 0 && len(pageBefore) > 0 {
+		BadParameter(c, w, "Bad Page Cursor Parameter", utils.PAGE_BEFORE, "Cannot page both after and before a cursor.")
+		return
+	}
+	_, offsetProvided := queryStrings[utils.PAGE_OFFSET]
+	if keyset && offsetProvided {
+		BadParameter(c, w, "Bad Page Offset Parameter", utils.PAGE_OFFSET, "Cannot page by offset and cursor.")
+		return
+	}
 
-	// extract filters
-	var validFilters []schema.Filter
-	for _, attribute := range m.Attributes {
-		filters, err := attribute.ValidateFilters(queryStrings)
-		if err != nil {
-			BadRequest(c, w, "Bad Filter Parameter", err.Error())
-			return
-		}
-		validFilters = append(validFilters, filters...)
+	// and how to count the total
+	countPolicy, countProvided, err := getCountPolicy(c, m, queryStrings)
+	if err != nil {
+		BadParameter(c, w, "Bad Page Count Parameter", utils.PAGE_COUNT, err.Error())
+		return
 	}
-	for _, relationship := range m.Relationships {
-		filters, err := relationship.ValidateFilters(queryStrings)
-		if err != nil {
-			BadRequest(c, w, "Bad Filter Parameter", err.Error())
-			return
-		}
-		validFilters = append(validFilters, filters...)
+
+	// extract filters, collecting every problem
+	validFilters, exceptions := validateFilters(c, m, queryStrings)
+	if len(exceptions) > 0 {
+		writeRequestError(c, w, validationRequestError(exceptions))
+		return
 	}
 
-	// create where clause from filters
+	// create where clause from scope
 	whereQueries := []string{}
 	whereArgs := []interface{}{}
+	whereQueries = append(whereQueries, scopeQueries...)
+	whereArgs = append(whereArgs, scopeArgs...)
+	// and from filters
 	for _, filter := range validFilters {
 		queries, args := filter.GetWhere(c, m.Table, m.IDColumn, len(whereArgs)+1)
 
@@ -86,45 +120,73 @@ func listGET(
 		whereClause = fmt.Sprintf("where %s", strings.Join(whereQueries, " and "))
 	}
 
-	countQuery := fmt.Sprintf(
-		`
-			select
-				count(*)
-			from %s
-			%s
-		`,
-		m.Table,
-		whereClause,
-	)
-	var count int
-	err = c.QueryRow(countQuery, whereArgs...).Scan(&count)
-	if err != nil {
-		panic(err)
-	}
-
 	// extract ordering
 	orders, err := GetStringParam(queryStrings, ORDER_ARG, "Ordering", m.DefaultOrder)
 	if err != nil {
-		BadRequest(c, w, "Bad Ordering Parameter", err.Error())
+		BadParameter(c, w, "Bad Ordering Parameter", ORDER_ARG, err.Error())
 		return
 	}
-	orderQuery, validatedOrderParam, err := m.GetOrderQuery(orders)
+	orderColumns, orderArgs, validatedOrderParam, err := m.GetOrderColumns(c, orders, len(whereArgs)+1)
 	if err != nil {
-		BadRequest(c, w, "Bad Ordering Parameter", err.Error())
+		BadParameter(c, w, "Bad Ordering Parameter", ORDER_ARG, err.Error())
 		return
 	}
 
-	instances, included, err := c.GetObjectsByFilter(
-		m,
-		whereQueries,
-		whereArgs,
-		orderQuery,
-		offset,
-		pageSize,
-		include,
-	)
-	if err != nil {
-		panic(err)
+	count := utils.UNKNOWN_TOTAL
+	if countPolicy == schema.COUNT_EXACT {
+		count = exactCount(c, m, whereClause, whereArgs)
+	}
+	estimate := utils.UNKNOWN_TOTAL
+	if countPolicy == schema.COUNT_ESTIMATE {
+		estimate = estimateCount(c, m, whereClause, whereArgs)
+	}
+
+	var instances, included []schema.Instance
+	var prevCursor, nextCursor *string
+	currentCursor := map[string][]string{}
+	if keyset {
+		var cursor []*string
+		if len(pageAfter) > 0 {
+			currentCursor[utils.PAGE_AFTER] = []string{pageAfter}
+			cursor, err = utils.DecodeCursor(pageAfter)
+		} else if len(pageBefore) > 0 {
+			currentCursor[utils.PAGE_BEFORE] = []string{pageBefore}
+			cursor, err = utils.DecodeCursor(pageBefore)
+		}
+		orderColumns = keysetOrder(m, orderColumns)
+		if err == nil && cursor != nil && len(cursor) != len(orderColumns) {
+			err = errors.New("Cursor does not match ordering.")
+		}
+		if err != nil {
+			BadRequest(c, w, "Bad Page Cursor Parameter", err.Error())
+			return
+		}
+		instances, included, prevCursor, nextCursor = getKeysetPage(
+			c,
+			m,
+			whereQueries,
+			whereArgs,
+			orderColumns,
+			orderArgs,
+			cursor,
+			len(pageBefore) > 0,
+			pageSize,
+			include,
+		)
+	} else {
+		instances, included, err = c.GetObjectsByFilter(
+			m,
+			whereQueries,
+			whereArgs,
+			schema.OrderQuery(orderColumns),
+			orderArgs,
+			offset,
+			pageSize,
+			include,
+		)
+		if err != nil {
+			panic(err)
+		}
 	}
 
 	validQueries := map[string][]string{}
@@ -133,7 +195,13 @@ func listGET(
 	}
 	validIncludeQuery := include.AsString()
 	if len(validIncludeQuery) > 0 {
-		validQueries["include"] = []string{validIncludeQuery}
+		validQueries[INCLUDE_ARG] = []string{validIncludeQuery}
+	}
+	for key, values := range include.FieldsAsQueries() {
+		validQueries[key] = values
+	}
+	if countProvided {
+		validQueries[utils.PAGE_COUNT] = []string{countPolicy}
 	}
 	for _, filter := range validFilters {
 		key := filter.GetQArgKey()
@@ -141,35 +209,49 @@ func listGET(
 		validQueries[key] = values
 	}
 
-	pageLinks := utils.GetPaginationLinks(
-		"https://"+r.Host+r.URL.Path,
-		pageOffset,
-		pageSize,
-		c.GetServer().GetDefaultDirectPageSize(),
-		count,
-		validQueries,
-	)
+	var pageLinks map[string]*string
+	if keyset {
+		pageLinks = utils.GetCursorPaginationLinks(
+			"https://"+r.Host+r.URL.Path,
+			pageSize,
+			c.GetServer().GetDefaultDirectPageSize(),
+			currentCursor,
+			prevCursor,
+			nextCursor,
+			validQueries,
+		)
+	} else {
+		pageLinks = utils.GetPaginationLinks(
+			"https://"+r.Host+r.URL.Path,
+			pageOffset,
+			pageSize,
+			c.GetServer().GetDefaultDirectPageSize(),
+			count,
+			validQueries,
+		)
+		// without a total a partial page must be the last
+		if count == utils.UNKNOWN_TOTAL && len(instances) < pageSize {
+			pageLinks["next"] = nil
+		}
+	}
 
 	pageMeta := map[string]interface{}{}
-	pageMeta["total"] = count
-	pageMeta["count"] = len(instances)
-
-	generalInstances := []interface{}{}
-	for _, instance := range instances {
-		generalInstances = append(generalInstances, instance)
+	if count != utils.UNKNOWN_TOTAL {
+		pageMeta["total"] = count
 	}
+	if estimate != utils.UNKNOWN_TOTAL {
+		pageMeta["estimated_total"] = estimate
+	}
+	pageMeta["count"] = len(instances)
 
 	responseBlob := schema.Page{
 		Links:    pageLinks,
 		Metadata: pageMeta,
-		Data:     generalInstances,
+		Data:     generalInstances(c, instances, include),
 	}
 	if len(included) > 0 {
 		uniqueIncluded := UniqueInstances(included)
-		var generalIncluded []interface{}
-		for _, instance := range uniqueIncluded {
-			generalIncluded = append(generalIncluded, instance)
-		}
+		generalIncluded := generalInstances(c, uniqueIncluded, include)
 		responseBlob.Included = &generalIncluded
 	}
 
@@ -2,8 +2,10 @@ package servers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -36,54 +38,124 @@ func listPOST(
 		return
 	}
 
+	// start a transaction
+	tx, err := c.Begin()
+	if err != nil {
+		panic(err)
+	}
+
+	// validate and insert the instance
+	newId, mapValues, err := createInstance(c, tx, m, instance)
+	if err != nil {
+		tx.Rollback()
+		writeRequestError(c, w, err)
+		return
+	}
+
+	// commit transaction
+	err = tx.Commit()
+	if err != nil {
+		panic(err)
+	}
+	c.GetServer().InvalidateCache(m.Type)
+	m.Manager.AfterCreate(c, newId, mapValues)
+
+	w.WriteHeader(http.StatusCreated)
+	// return created object as though it were a GET
+	detailGET(w, r, c, m, newId, include)
+}
+
+// validates a new instance and inserts it within the transaction
+// returns the new id and the values created with
+func createInstance(
+	c schema.Context,
+	tx schema.Transaction,
+	m *schema.Model,
+	instance schema.Instance,
+) (
+	string,
+	map[string]interface{},
+	error,
+) {
+	err := authoriseCreate(c, m)
+	if err != nil {
+		return "", nil, err
+	}
+
+	exceptions := []Exception{}
+
 	// user cannot choose their own id
 	if len(instance.GetID()) != 0 {
-		BadRequest(c, w, "Bad Object Value", "ID's are assigned not chosen.")
-		return
+		exceptions = append(exceptions, validationExceptions(
+			"Bad Object Value",
+			errors.New("ID's are assigned not chosen."),
+			"/data/id",
+		)...)
 	}
 	// type cannot be messed with
 	instanceType := instance.GetType()
 	if !(len(instanceType) == 0 || instanceType == m.Type) {
-		BadRequest(c, w, "Bad Object Value", "Type does not match endpoint model.")
-		return
+		exceptions = append(exceptions, validationExceptions(
+			"Bad Object Value",
+			errors.New("Type does not match endpoint model."),
+			"/data/type",
+		)...)
 	}
 
-	// load defaults and validate values
+	// load defaults and validate values, collecting every problem
 	mapValues := instance.GetValues()
 	values := valuesFromMap(mapValues, m.Attributes, m.Relationships)
 	valueIndex := 0
 	for _, attribute := range m.Attributes {
-		values[valueIndex], err = attribute.DefaultFallback(values[valueIndex], instance)
-		if err != nil {
-			BadRequest(c, w, "Bad Attribute Value", err.Error())
-			return
+		pointer := utils.ATTRIBUTES_POINTER + attribute.GetKey()
+		// read only values are left to their defaults
+		if !schema.Creatable(attribute.GetAccess()) {
+			values[valueIndex] = nil
 		}
-		// nil values are not included in the insert statement (use db default)
 		if values[valueIndex] != nil {
+			err = checkWritable(c, m, instance, utils.AttributeError, attribute.GetKey())
+			if err != nil {
+				exceptions = append(exceptions, validationExceptions("Bad Attribute Value", err, pointer)...)
+			}
+		}
+		values[valueIndex], err = attribute.DefaultFallback(values[valueIndex], instance)
+		if err != nil {
+			exceptions = append(exceptions, validationExceptions("Bad Attribute Value", err, pointer)...)
+		} else if values[valueIndex] != nil {
+			// nil values are not included in the insert statement (use db default)
 			values[valueIndex], err = attribute.Validate(values[valueIndex])
 			if err != nil {
-				BadRequest(c, w, "Bad Attribute Value", err.Error())
-				return
+				exceptions = append(exceptions, validationExceptions("Bad Attribute Value", err, pointer)...)
 			}
 		}
 		valueIndex += 1
 	}
 	for _, relation := range m.Relationships {
-		values[valueIndex], err = relation.DefaultFallback(c, values[valueIndex], instance)
-		if err != nil {
-			BadRequest(c, w, "Bad Relationship Value", err.Error())
-			return
+		pointer := utils.RELATIONSHIPS_POINTER + relation.GetKey()
+		if !schema.Creatable(relation.GetAccess()) {
+			values[valueIndex] = nil
 		}
-		// nil values are ignored
 		if values[valueIndex] != nil {
+			err = checkWritable(c, m, instance, utils.RelationshipError, relation.GetKey())
+			if err != nil {
+				exceptions = append(exceptions, validationExceptions("Bad Relationship Value", err, pointer)...)
+			}
+		}
+		values[valueIndex], err = relation.DefaultFallback(c, values[valueIndex], instance)
+		if err != nil {
+			exceptions = append(exceptions, validationExceptions("Bad Relationship Value", err, pointer)...)
+		} else if values[valueIndex] != nil {
+			// nil values are ignored
 			values[valueIndex], err = relation.Validate(c, values[valueIndex])
 			if err != nil {
-				BadRequest(c, w, "Bad Relationship Value", err.Error())
-				return
+				exceptions = append(exceptions, validationExceptions("Bad Relationship Value", err, pointer)...)
 			}
 		}
 		valueIndex += 1
 	}
+	if len(exceptions) > 0 {
+		return "", nil, validationRequestError(exceptions)
+	}
 
 	// run manager validation
 	mapValues = mapFromValues(values, m.Attributes, m.Relationships)
@@ -91,13 +163,11 @@ func listPOST(
 	// instance.SetValues(mapValues)
 	err = m.Manager.BeforeCreate(c, mapValues)
 	if err != nil {
-		BadRequest(c, w, "Bad New Instance", err.Error())
-		return
+		return "", nil, validationRequestError(validationExceptions("Bad New Instance", err, "/data"))
 	}
 	err = m.Manager.BeforeSave(c, mapValues)
 	if err != nil {
-		BadRequest(c, w, "Bad Instance", err.Error())
-		return
+		return "", nil, validationRequestError(validationExceptions("Bad Instance", err, "/data"))
 	}
 
 	// build insert query
@@ -152,12 +222,6 @@ func listPOST(
 		)
 	}
 
-	// start a transaction
-	tx, err := c.Begin()
-	if err != nil {
-		panic(err)
-	}
-
 	// execute insert query
 	var newId string
 	err = tx.QueryRow(query, insertValues...).Scan(&newId)
@@ -186,13 +250,11 @@ func listPOST(
 		}
 	}
 
-	// commit transaction
-	err = tx.Commit()
+	// run manager transaction hook
+	err = m.Manager.DuringCreate(c, tx, newId, mapValues)
 	if err != nil {
-		panic(err)
+		return "", nil, validationRequestError(validationExceptions("Bad New Instance", err, "/data"))
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	// return created object as though it were a GET
-	detailGET(w, r, c, m, newId, include)
+	return newId, mapValues, nil
 }
@@ -0,0 +1,58 @@
+package servers
+
+import (
+	"github.com/bor3ham/reja/schema"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// discards the body of responses to HEAD requests
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w headResponseWriter) Write(body []byte) (int, error) {
+	return len(body), nil
+}
+
+// the methods an endpoint answers, including those handled automatically
+func allowHeader(methods []string) string {
+	allowed := map[string]bool{
+		http.MethodOptions: true,
+	}
+	for _, method := range methods {
+		allowed[method] = true
+		if method == http.MethodGet {
+			allowed[http.MethodHead] = true
+		}
+	}
+	sorted := []string{}
+	for method, _ := range allowed {
+		sorted = append(sorted, method)
+	}
+	sort.Strings(sorted)
+	return strings.Join(sorted, ", ")
+}
+
+// answers OPTIONS and unsupported methods, otherwise returns the method to handle
+// HEAD requests are handled as GET with the body discarded
+func allowMethods(c schema.Context, w http.ResponseWriter, methods ...string) (string, bool) {
+	method := c.GetRequest().Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+
+	if method == http.MethodOptions {
+		w.Header().Set("Allow", allowHeader(methods))
+		w.WriteHeader(http.StatusNoContent)
+		return "", false
+	}
+	for _, allowed := range methods {
+		if method == allowed {
+			return method, true
+		}
+	}
+	MethodNotAllowed(c, w, methods)
+	return "", false
+}
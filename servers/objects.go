@@ -15,20 +15,15 @@ type RelationResult struct {
 	Default      interface{}
 	Values       map[string]interface{}
 	RelationMaps map[string]map[string][]string
+	// raised again by the request once every lookup has finished
+	Panic interface{}
 }
 
 type IncludeResult struct {
 	Instances []schema.Instance
 	Included  []schema.Instance
 	Error     error
-}
-
-func flattened(fields [][]interface{}) []interface{} {
-	var flatList []interface{}
-	for _, relation := range fields {
-		flatList = append(flatList, relation...)
-	}
-	return flatList
+	Panic     interface{}
 }
 
 func valuesFromMap(
@@ -96,6 +91,21 @@ func combineRelations(
 	return combinedMap
 }
 
+// leaves out relations hidden from the user on the instance, so they are not included
+func (rc *RequestContext) visibleRelations(
+	m *schema.Model,
+	instance schema.Instance,
+	relations map[string]map[string][]string,
+) map[string]map[string][]string {
+	visible := map[string]map[string][]string{}
+	for key, relation := range relations {
+		if m.Manager.CanReadField(rc.GetUser(), instance, key) {
+			visible[key] = relation
+		}
+	}
+	return visible
+}
+
 func (rc *RequestContext) GetObjectsByIDsAllRelations(
 	m *schema.Model,
 	objectIds []string,
@@ -105,7 +115,7 @@ func (rc *RequestContext) GetObjectsByIDsAllRelations(
 	[]schema.Instance,
 	error,
 ) {
-	return rc.getObjects(m, objectIds, []string{}, []interface{}{}, "", 0, 0, include, true)
+	return rc.getObjects(m, objectIds, []string{}, []interface{}{}, "", []interface{}{}, 0, 0, include, true)
 }
 
 func (rc *RequestContext) GetObjectsByIDs(
@@ -117,7 +127,7 @@ func (rc *RequestContext) GetObjectsByIDs(
 	[]schema.Instance,
 	error,
 ) {
-	return rc.getObjects(m, objectIds, []string{}, []interface{}{}, "", 0, 0, include, false)
+	return rc.getObjects(m, objectIds, []string{}, []interface{}{}, "", []interface{}{}, 0, 0, include, false)
 }
 
 func (rc *RequestContext) GetObjectsByFilter(
@@ -125,6 +135,7 @@ func (rc *RequestContext) GetObjectsByFilter(
 	whereQueries []string,
 	whereArgs []interface{},
 	orderQuery string,
+	orderArgs []interface{},
 	offset int,
 	limit int,
 	include *schema.Include,
@@ -133,7 +144,7 @@ func (rc *RequestContext) GetObjectsByFilter(
 	[]schema.Instance,
 	error,
 ) {
-	return rc.getObjects(m, []string{}, whereQueries, whereArgs, orderQuery, offset, limit, include, false)
+	return rc.getObjects(m, []string{}, whereQueries, whereArgs, orderQuery, orderArgs, offset, limit, include, false)
 }
 
 func (rc *RequestContext) getObjects(
@@ -145,6 +156,7 @@ func (rc *RequestContext) getObjects(
 	whereQueries []string,
 	whereArgs []interface{},
 	orderQuery string,
+	orderArgs []interface{},
 	offset int,
 	limit int,
 
@@ -159,17 +171,48 @@ func (rc *RequestContext) getObjects(
 	var cacheHits []schema.Instance
 	var cacheMaps []map[string]map[string][]string
 
+	// skip values outside of a sparse fieldset, unless needed for included relations
+	selectedAttributes := make([]bool, len(m.Attributes))
+	selectedRelationships := make([]bool, len(m.Relationships))
+	columns := []string{m.IDColumn}
+	for index, attribute := range m.Attributes {
+		selectedAttributes[index] = include.Selects(m.Type, attribute.GetKey())
+		if selectedAttributes[index] {
+			attributeColumns, _ := attribute.GetSelectDirect()
+			for _, column := range attributeColumns {
+				columns = append(columns, schema.TableExpression(column, m.Table))
+			}
+		}
+	}
+	for index, relationship := range m.Relationships {
+		including := include != nil && include.Children[relationship.GetKey()] != nil
+		selectedRelationships[index] = including || include.Selects(m.Type, relationship.GetKey())
+		if selectedRelationships[index] {
+			extraColumns, _ := relationship.GetSelectExtra()
+			columns = append(columns, extraColumns...)
+		}
+	}
+
 	var query string
 	var args []interface{}
-	columns, _ := m.DirectFields()
-	extraColumns, _ := m.ExtraFields()
-	columns = append(columns, extraColumns...)
 	if len(objectIds) > 0 {
 		// attempt to use cache
 		var newIds []string
 
 		for _, id := range objectIds {
-			instance, relationMap := rc.GetCachedObject(m.Type, id)
+			// cached instances may hold paginated relations
+			var instance schema.Instance
+			var relationMap map[string]map[string][]string
+			if !allRelations {
+				instance, relationMap = rc.GetCachedObject(m.Type, id)
+			}
+			// fall back to instances cached by earlier requests, which hold paginated relations
+			if instance == nil && !allRelations && USE_OBJECT_CACHE {
+				instance, relationMap = rc.getSharedObject(m, id)
+				if instance != nil {
+					rc.CacheObject(instance, relationMap)
+				}
+			}
 			if instance != nil && USE_OBJECT_CACHE {
 				cacheHits = append(cacheHits, instance)
 				cacheMaps = append(cacheMaps, relationMap)
@@ -202,8 +245,10 @@ func (rc *RequestContext) getObjects(
 		whereClause := ""
 		if len(whereQueries) > 0 {
 			whereClause = fmt.Sprintf("where %s", strings.Join(whereQueries, " and "))
-			args = append(args, whereArgs...)
 		}
+		// order arguments are numbered after any where arguments
+		args = append(args, whereArgs...)
+		args = append(args, orderArgs...)
 
 		query = fmt.Sprintf(
 			`
@@ -230,8 +275,11 @@ func (rc *RequestContext) getObjects(
 	listRelations := map[string]map[string][]string{}
 
 	if len(query) > 0 {
+		generation := rc.Server.GetCacheGeneration(m.Type)
+		rc.lockQueries()
 		rows, err := rc.Query(query, args...)
 		if err != nil {
+			rc.unlockQueries()
 			return []schema.Instance{}, []schema.Instance{}, err
 		}
 		defer rows.Close()
@@ -241,18 +289,31 @@ func (rc *RequestContext) getObjects(
 		extraFields := [][][]interface{}{}
 		for rows.Next() {
 			var id string
-			_, fields := m.DirectFields()
+			scanFields := []interface{}{}
+			scanFields = append(scanFields, &id)
+			// unselected values are left empty
+			fields := []interface{}{}
+			for index, attribute := range m.Attributes {
+				_, vars := attribute.GetSelectDirect()
+				fields = append(fields, vars...)
+				if selectedAttributes[index] {
+					scanFields = append(scanFields, vars...)
+				}
+			}
 			instanceFields = append(instanceFields, fields)
-			_, extras := m.ExtraFields()
+			extras := [][]interface{}{}
+			for index, relationship := range m.Relationships {
+				_, vars := relationship.GetSelectExtra()
+				extras = append(extras, vars)
+				if selectedRelationships[index] {
+					scanFields = append(scanFields, vars...)
+				}
+			}
 			extraFields = append(extraFields, extras)
-			flatExtras := flattened(extras)
 
-			scanFields := []interface{}{}
-			scanFields = append(scanFields, &id)
-			scanFields = append(scanFields, fields...)
-			scanFields = append(scanFields, flatExtras...)
 			err := rows.Scan(scanFields...)
 			if err != nil {
+				rc.unlockQueries()
 				return []schema.Instance{}, []schema.Instance{}, err
 			}
 
@@ -262,14 +323,31 @@ func (rc *RequestContext) getObjects(
 
 			ids = append(ids, id)
 		}
+		err = rows.Err()
+		if err != nil {
+			panic(err)
+		}
+		rc.unlockQueries()
 
 		var wg sync.WaitGroup
-		relationResults := make(chan RelationResult)
 		relationships := m.Relationships
-		wg.Add(len(relationships))
+		// buffered so lookups run inline can report without a reader
+		relationResults := make(chan RelationResult, len(relationships))
 		for relationIndex, relationship := range relationships {
-			go func(wg *sync.WaitGroup, index int, relation schema.Relationship) {
-				defer wg.Done()
+			if !selectedRelationships[relationIndex] {
+				continue
+			}
+			index := relationIndex
+			relation := relationship
+			rc.workers.run(&wg, func() {
+				defer func() {
+					if err := recover(); err != nil {
+						relationResults <- RelationResult{
+							Index: index,
+							Panic: err,
+						}
+					}
+				}()
 				var relationExtras [][]interface{}
 				for _, result := range extraFields {
 					relationExtras = append(relationExtras, result[index])
@@ -279,7 +357,11 @@ func (rc *RequestContext) getObjects(
 				if !allRelations {
 					pageSize = rc.Server.GetIndirectPageSize()
 				}
-				values, maps := relation.GetValues(rc, m, ids, relationExtras, 0, pageSize)
+				values, maps := func() (map[string]interface{}, map[string]map[string][]string) {
+					rc.lockQueries()
+					defer rc.unlockQueries()
+					return relation.GetValues(rc, m, ids, relationExtras, 0, pageSize)
+				}()
 				relationResults <- RelationResult{
 					Index:        index,
 					Key:          relation.GetKey(),
@@ -287,7 +369,7 @@ func (rc *RequestContext) getObjects(
 					Values:       values,
 					RelationMaps: maps,
 				}
-			}(&wg, relationIndex, relationship)
+			})
 		}
 		go func(wg *sync.WaitGroup) {
 			wg.Wait()
@@ -297,13 +379,24 @@ func (rc *RequestContext) getObjects(
 		relationDefaults := make([]interface{}, len(relationships))
 		relationValues := make([]map[string]interface{}, len(relationships))
 		relationMaps := make([]map[string]map[string][]string, len(relationships))
+		for relationIndex, relationship := range relationships {
+			relationDefaults[relationIndex] = relationship.GetDefaultValue()
+		}
 
+		var relationPanic interface{}
 		for result := range relationResults {
+			if result.Panic != nil {
+				relationPanic = result.Panic
+				continue
+			}
 			// re order relation results
 			relationDefaults[result.Index] = result.Default
 			relationValues[result.Index] = result.Values
 			relationMaps[result.Index] = result.RelationMaps
 		}
+		if relationPanic != nil {
+			panic(relationPanic)
+		}
 
 		for index, instance := range instances {
 			instanceRelations := map[string]map[string][]string{}
@@ -335,74 +428,105 @@ func (rc *RequestContext) getObjects(
 			}
 			instance.SetValues(mapFromValues(instanceFields[index], m.Attributes, m.Relationships))
 			// add complete relation map to flat map
-			listRelations = combineRelations(listRelations, instanceRelations)
-			// add instance to cache
-			rc.CacheObject(instance, instanceRelations)
+			listRelations = combineRelations(listRelations, rc.visibleRelations(m, instance, instanceRelations))
+			// add instance to cache, unless missing values
+			if !include.Restricts(m.Type) {
+				rc.CacheObject(instance, instanceRelations)
+				if !allRelations {
+					rc.storeSharedObject(m, instance, instanceRelations, generation)
+				}
+			}
 		}
 	}
 
 	// add cached instances and maps
 	instances = append(instances, cacheHits...)
-	for _, cacheMap := range cacheMaps {
-		listRelations = combineRelations(listRelations, cacheMap)
+	for index, cacheMap := range cacheMaps {
+		listRelations = combineRelations(listRelations, rc.visibleRelations(m, cacheHits[index], cacheMap))
 	}
 
-	var wg sync.WaitGroup
-	includedResults := make(chan IncludeResult)
+	// only relations that are included need looking up
+	type includeLookup struct {
+		model         *schema.Model
+		ids           []string
+		childIncludes *schema.Include
+	}
+	lookups := []includeLookup{}
 	for attribute, modelTypes := range listRelations {
 		for modelType, ids := range modelTypes {
 			childModel := rc.GetServer().GetModel(modelType)
 			if childModel == nil {
 				panic(fmt.Sprintf("Could not find model for model: %s", modelType))
 			}
+			if include == nil {
+				continue
+			}
+			childIncludes, exists := include.Children[attribute]
+			if !exists {
+				continue
+			}
+			lookups = append(lookups, includeLookup{
+				model:         childModel,
+				ids:           ids,
+				childIncludes: childIncludes,
+			})
+		}
+	}
 
-			wg.Add(1)
-			go func(
-				wg *sync.WaitGroup,
-				rc *RequestContext,
-				include *schema.Include,
-				model *schema.Model,
-				attribute string,
-			) {
-				defer wg.Done()
-
-				if include != nil {
-					childIncludes, exists := include.Children[attribute]
-					if exists {
-						childInstances, childIncluded, err := rc.GetObjectsByIDs(
-							model,
-							ids,
-							childIncludes,
-						)
-						if err != nil {
-							includedResults <- IncludeResult{
-								Error: err,
-							}
-						} else {
-							includedResults <- IncludeResult{
-								Instances: childInstances,
-								Included:  childIncluded,
-								Error:     nil,
-							}
-						}
-
+	var wg sync.WaitGroup
+	includedResults := make(chan IncludeResult, len(lookups))
+	for _, lookup := range lookups {
+		lookup := lookup
+		rc.workers.run(&wg, func() {
+			defer func() {
+				if err := recover(); err != nil {
+					includedResults <- IncludeResult{
+						Panic: err,
 					}
 				}
-			}(&wg, rc, include, childModel, attribute)
-		}
+			}()
+
+			childInstances, childIncluded, err := rc.GetObjectsByIDs(
+				lookup.model,
+				lookup.ids,
+				lookup.childIncludes,
+			)
+			if err != nil {
+				includedResults <- IncludeResult{
+					Error: err,
+				}
+			} else {
+				includedResults <- IncludeResult{
+					Instances: childInstances,
+					Included:  childIncluded,
+					Error:     nil,
+				}
+			}
+		})
 	}
 	go func(wg *sync.WaitGroup) {
 		wg.Wait()
 		close(includedResults)
 	}(&wg)
 	var included []schema.Instance
+	var includeErr error
+	var includePanic interface{}
+	// drain every result so no lookup is left blocked
 	for result := range includedResults {
-		if result.Error != nil {
-			return []schema.Instance{}, []schema.Instance{}, result.Error
+		if result.Panic != nil {
+			includePanic = result.Panic
+		} else if result.Error != nil {
+			includeErr = result.Error
 		}
 		included = append(included, result.Instances...)
 		included = append(included, result.Included...)
 	}
+	if includePanic != nil {
+		panic(includePanic)
+	}
+	if includeErr != nil {
+		return []schema.Instance{}, []schema.Instance{}, includeErr
+	}
 
 	return instances, included, nil
 }
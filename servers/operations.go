@@ -0,0 +1,403 @@
+package servers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+const ATOMIC_OPERATIONS = "atomic:operations"
+const ATOMIC_RESULTS = "atomic:results"
+
+const OP_ADD = "add"
+const OP_UPDATE = "update"
+const OP_REMOVE = "remove"
+
+// the method each operation stands in for
+var OP_METHODS = map[string]string{
+	OP_ADD:    "POST",
+	OP_UPDATE: "PATCH",
+	OP_REMOVE: "DELETE",
+}
+
+type OperationRef struct {
+	Type         string `json:"type"`
+	ID           string `json:"id"`
+	LID          string `json:"lid"`
+	Relationship string `json:"relationship"`
+}
+
+type Operation struct {
+	Op   string                 `json:"op"`
+	Ref  *OperationRef          `json:"ref"`
+	Data map[string]interface{} `json:"data"`
+}
+
+// local ids assigned by the client, mapped to real ids by type
+type localIDs map[string]map[string]string
+
+func (lids localIDs) resolve(modelType string, lid string) (string, error) {
+	id, exists := lids[modelType][lid]
+	if !exists {
+		return "", errors.New(fmt.Sprintf("Unknown local ID %s for type %s.", lid, modelType))
+	}
+	return id, nil
+}
+
+func (lids localIDs) assign(modelType string, lid string, id string) error {
+	_, exists := lids[modelType]
+	if !exists {
+		lids[modelType] = map[string]string{}
+	}
+	_, exists = lids[modelType][lid]
+	if exists {
+		return errors.New(fmt.Sprintf("Local ID %s for type %s is used more than once.", lid, modelType))
+	}
+	lids[modelType][lid] = id
+	return nil
+}
+
+// replaces a local id in a resource identifier with the real id
+func (lids localIDs) resolveIdentifier(identifier map[string]interface{}) error {
+	lid, exists := identifier["lid"]
+	if !exists {
+		return nil
+	}
+	lidString, ok := lid.(string)
+	if !ok {
+		return errors.New("Local ID must be a string.")
+	}
+	modelType, _ := identifier["type"].(string)
+	id, err := lids.resolve(modelType, lidString)
+	if err != nil {
+		return err
+	}
+	delete(identifier, "lid")
+	identifier["id"] = id
+	return nil
+}
+
+// replaces local ids in the relationships of a resource object
+func (lids localIDs) resolveRelationships(data map[string]interface{}) error {
+	relationships, ok := data["relationships"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for _, relationship := range relationships {
+		relationshipMap, ok := relationship.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch linkage := relationshipMap["data"].(type) {
+		case map[string]interface{}:
+			err := lids.resolveIdentifier(linkage)
+			if err != nil {
+				return err
+			}
+		case []interface{}:
+			for _, item := range linkage {
+				identifier, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				err := lids.resolveIdentifier(identifier)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// points exceptions at the operation that raised them
+func operationExceptions(index int, exceptions []Exception) []Exception {
+	prefix := fmt.Sprintf("/%s/%d", ATOMIC_OPERATIONS, index)
+	pointed := []Exception{}
+	for _, exception := range exceptions {
+		if exception.Source == nil {
+			exception.Source = &ExceptionSource{}
+		}
+		if len(exception.Source.Parameter) == 0 {
+			source := *exception.Source
+			source.Pointer = prefix + source.Pointer
+			exception.Source = &source
+		}
+		pointed = append(pointed, exception)
+	}
+	return pointed
+}
+
+func OperationsHandler(s schema.Server, w http.ResponseWriter, r *http.Request) {
+	rc := NewRequestContext(s, w, r)
+	w = rc.ResponseWriter
+	defer catchExceptions(rc, w)()
+	_, allowed := allowMethods(rc, w, "POST")
+	if !allowed {
+		return
+	}
+	err := rc.Authenticate()
+	if err != nil {
+		return
+	}
+	if !rateLimit(rc, w, nil, "POST") {
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		panic(err)
+	}
+	var document map[string][]Operation
+	err = json.Unmarshal(body, &document)
+	if err != nil {
+		BadRequest(rc, w, "Unable to Parse JSON", err.Error())
+		return
+	}
+	operations := document[ATOMIC_OPERATIONS]
+	if len(operations) == 0 {
+		BadRequest(rc, w, "Bad Operations", "No operations provided.")
+		return
+	}
+
+	// run every operation within one transaction, so later operations can see earlier ones
+	tx, err := rc.BeginBound()
+	if err != nil {
+		panic(err)
+	}
+	// does nothing once committed
+	defer tx.Rollback()
+
+	lids := localIDs{}
+	results := []interface{}{}
+	afterCommit := []func(){}
+	for index, operation := range operations {
+		result, after, err := runOperation(rc, tx, operation, lids)
+		if err != nil {
+			requestError, ok := err.(RequestError)
+			if ok {
+				requestError.Exceptions = operationExceptions(index, requestError.Exceptions)
+				err = requestError
+			}
+			tx.Rollback()
+			writeRequestError(rc, w, err)
+			return
+		}
+		results = append(results, result)
+		afterCommit = append(afterCommit, after)
+	}
+
+	// commit transaction
+	err = tx.Commit()
+	if err != nil {
+		panic(err)
+	}
+	for _, after := range afterCommit {
+		after()
+	}
+
+	rc.WriteToResponse(map[string]interface{}{
+		ATOMIC_RESULTS: results,
+	})
+
+	rc.LogStats()
+}
+
+// performs a single operation in the transaction
+// returns its result and the manager hook to run once committed
+func runOperation(
+	c schema.Context,
+	tx schema.Transaction,
+	operation Operation,
+	lids localIDs,
+) (
+	interface{},
+	func(),
+	error,
+) {
+	noInclude := schema.Include{
+		Children: map[string]*schema.Include{},
+	}
+
+	// find the target of the operation
+	var modelType, id, lid string
+	if operation.Ref != nil {
+		if len(operation.Ref.Relationship) > 0 {
+			return nil, nil, badRequestError("Bad Operation", "Relationship operations are not supported.")
+		}
+		modelType = operation.Ref.Type
+		id = operation.Ref.ID
+		lid = operation.Ref.LID
+	} else if operation.Data != nil {
+		modelType, _ = operation.Data["type"].(string)
+		id, _ = operation.Data["id"].(string)
+		lid, _ = operation.Data["lid"].(string)
+	}
+	m := c.GetServer().GetModel(modelType)
+	if m == nil {
+		return nil, nil, badRequestError("Bad Operation", fmt.Sprintf("Unknown type %s.", modelType))
+	}
+	method, exists := OP_METHODS[operation.Op]
+	if exists && !m.AllowsMethod(method) {
+		return nil, nil, RequestError{
+			Status: http.StatusMethodNotAllowed,
+			Exceptions: []Exception{
+				Exception{
+					Status: strconv.Itoa(http.StatusMethodNotAllowed),
+					Title:  "Method Not Allowed",
+					Detail: fmt.Sprintf("Type %s does not support %s operations.", m.Type, operation.Op),
+				},
+			},
+		}
+	}
+	if operation.Op != OP_ADD && len(lid) > 0 {
+		var err error
+		id, err = lids.resolve(modelType, lid)
+		if err != nil {
+			return nil, nil, badRequestError("Bad Operation", err.Error())
+		}
+	}
+
+	// parse the operation data into an instance
+	var instance schema.Instance
+	if operation.Op == OP_ADD || operation.Op == OP_UPDATE {
+		if operation.Data == nil {
+			return nil, nil, badRequestError("Bad Operation", "Operation data missing.")
+		}
+		// the primary local id is already resolved, or assigned once created
+		delete(operation.Data, "lid")
+		if operation.Op == OP_UPDATE {
+			operation.Data["id"] = id
+		}
+		err := lids.resolveRelationships(operation.Data)
+		if err != nil {
+			return nil, nil, badRequestError("Bad Operation", err.Error())
+		}
+		data, err := json.Marshal(operation.Data)
+		if err != nil {
+			panic(err)
+		}
+		instance = m.Manager.Create()
+		err = json.Unmarshal(data, instance)
+		if err != nil {
+			return nil, nil, badRequestError("Unable to Parse JSON", err.Error())
+		}
+	}
+
+	// load the existing instance
+	var existing schema.Instance
+	if operation.Op == OP_UPDATE || operation.Op == OP_REMOVE {
+		if len(id) == 0 {
+			return nil, nil, badRequestError("Bad Operation", "Operation target has no ID.")
+		}
+		// earlier operations leave paginated copies in the cache
+		c.FlushCache()
+		instances, _, err := c.GetObjectsByIDsAllRelations(m, []string{id}, &noInclude)
+		if err != nil {
+			panic(err)
+		}
+		if len(instances) == 0 {
+			return nil, nil, RequestError{
+				Status: http.StatusNotFound,
+				Exceptions: []Exception{
+					Exception{
+						Status: strconv.Itoa(http.StatusNotFound),
+						Code:   utils.CODE_NOT_FOUND,
+						Title:  "Not Found",
+						Detail: fmt.Sprintf("No %s found with ID '%s'.", m.Type, id),
+					},
+				},
+			}
+		}
+		if !c.CanAccessAllInstances(instances) {
+			return nil, nil, RequestError{
+				Status: http.StatusForbidden,
+				Exceptions: []Exception{
+					Exception{
+						Status: strconv.Itoa(http.StatusForbidden),
+						Code:   utils.CODE_FORBIDDEN,
+						Title:  "Forbidden",
+						Detail: "You do not have access to this object.",
+					},
+				},
+			}
+		}
+		existing = instances[0]
+		if operation.Op == OP_UPDATE {
+			err = authoriseUpdate(c, m, existing)
+		} else {
+			err = authoriseDelete(c, m, existing)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var after func()
+	switch operation.Op {
+	case OP_ADD:
+		newId, values, err := createInstance(c, tx, m, instance)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(lid) > 0 {
+			err = lids.assign(m.Type, lid, newId)
+			if err != nil {
+				return nil, nil, badRequestError("Bad Operation", err.Error())
+			}
+		}
+		id = newId
+		after = func() {
+			c.GetServer().InvalidateCache(m.Type)
+			m.Manager.AfterCreate(c, newId, values)
+		}
+	case OP_UPDATE:
+		originalsMap := existing.GetValues()
+		originals := valuesFromMap(originalsMap, m.Attributes, m.Relationships)
+		updates := valuesFromMap(instance.GetValues(), m.Attributes, m.Relationships)
+		err := validateUpdates(c, m, existing, originals, updates)
+		if err != nil {
+			return nil, nil, err
+		}
+		updatesMap, err := writeUpdates(c, tx, m, id, originalsMap, originals, updates)
+		if err != nil {
+			return nil, nil, err
+		}
+		after = func() {
+			c.GetServer().InvalidateCache(m.Type)
+			m.Manager.AfterUpdate(c, id, originalsMap, updatesMap)
+		}
+	case OP_REMOVE:
+		values := existing.GetValues()
+		err := deleteInstance(c, tx, m, id, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		c.FlushCache()
+		after = func() {
+			c.GetServer().InvalidateCache(m.Type)
+			m.Manager.AfterDelete(c, id, values)
+		}
+		return map[string]interface{}{}, after, nil
+	default:
+		return nil, nil, badRequestError("Bad Operation", fmt.Sprintf("Unknown operation %s.", operation.Op))
+	}
+
+	// return the instance as it now stands
+	c.FlushCache()
+	instances, _, err := c.GetObjectsByIDs(m, []string{id}, &noInclude)
+	if err != nil {
+		panic(err)
+	}
+	if len(instances) == 0 {
+		return map[string]interface{}{}, after, nil
+	}
+	return map[string]interface{}{
+		"data": generalInstances(c, instances, &noInclude)[0],
+	}, after, nil
+}
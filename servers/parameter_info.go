@@ -5,6 +5,28 @@ import (
 	"net/http"
 )
 
+type FieldDescription struct {
+	Key       string   `json:"key"`
+	Readable  bool     `json:"readable"`
+	Creatable bool     `json:"creatable"`
+	Updatable bool     `json:"updatable"`
+	Choices   []string `json:"choices,omitempty"`
+}
+
+// implemented by attributes restricted to a fixed set of values
+type choiceAttribute interface {
+	GetChoices() []string
+}
+
+func describeField(key string, access string) FieldDescription {
+	return FieldDescription{
+		Key:       key,
+		Readable:  schema.Readable(access),
+		Creatable: schema.Creatable(access),
+		Updatable: schema.Updatable(access),
+	}
+}
+
 func ParameterInfoHandler(
 	s schema.Server,
 	m *schema.Model,
@@ -12,22 +34,45 @@ func ParameterInfoHandler(
 	r *http.Request,
 ) {
 	rc := NewRequestContext(s, w, r)
+	w = rc.ResponseWriter
+	defer catchExceptions(rc, w)()
+	_, allowed := allowMethods(rc, w, "GET")
+	if !allowed {
+		return
+	}
 	err := rc.Authenticate()
 	if err != nil {
 		return
 	}
+	if !rateLimit(rc, w, m, "GET") {
+		return
+	}
 
 	filters := []interface{}{}
+	fields := []FieldDescription{}
 	for _, attribute := range m.Attributes {
-		filters = append(filters, attribute.AvailableFilters()...)
+		field := describeField(attribute.GetKey(), attribute.GetAccess())
+		if choices, ok := attribute.(choiceAttribute); ok {
+			field.Choices = choices.GetChoices()
+		}
+		fields = append(fields, field)
+		// write only values cannot be filtered on
+		if schema.Readable(attribute.GetAccess()) {
+			filters = append(filters, attribute.AvailableFilters()...)
+		}
 	}
 	for _, relationship := range m.Relationships {
-		filters = append(filters, relationship.AvailableFilters()...)
+		fields = append(fields, describeField(relationship.GetKey(), relationship.GetAccess()))
+		if schema.Readable(relationship.GetAccess()) {
+			filters = append(filters, relationship.AvailableFilters()...)
+		}
 	}
 
 	responseBlob := struct {
-		Filters []interface{} `json:"filters"`
+		Fields  []FieldDescription `json:"fields"`
+		Filters []interface{}      `json:"filters"`
 	}{
+		Fields:  fields,
 		Filters: filters,
 	}
 
@@ -0,0 +1,163 @@
+package servers
+
+import (
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time
+}
+
+// token buckets held in memory, only suitable for a single server process
+type MemoryRateLimiter struct {
+	mutex   sync.Mutex
+	buckets map[string]*tokenBucket
+	swept   time.Time
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		buckets: map[string]*tokenBucket{},
+		swept:   time.Now(),
+	}
+}
+
+func (limiter *MemoryRateLimiter) Take(key string, limit schema.RateLimit) schema.RateLimitResult {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	now := time.Now()
+	limiter.sweep(now)
+
+	capacity := float64(limit.Requests)
+	perSecond := capacity / limit.Per.Seconds()
+	bucket, exists := limiter.buckets[key]
+	if !exists {
+		bucket = &tokenBucket{
+			tokens:  capacity,
+			updated: now,
+		}
+		limiter.buckets[key] = bucket
+	}
+
+	// refill for the time passed since last taken
+	bucket.tokens = math.Min(capacity, bucket.tokens+now.Sub(bucket.updated).Seconds()*perSecond)
+	bucket.updated = now
+
+	result := schema.RateLimitResult{}
+	if bucket.tokens >= 1 {
+		bucket.tokens -= 1
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsDuration((1 - bucket.tokens) / perSecond)
+	}
+	result.Remaining = int(bucket.tokens)
+	result.Reset = secondsDuration((capacity - bucket.tokens) / perSecond)
+	bucket.full = now.Add(result.Reset)
+	return result
+}
+
+// forgets buckets that have refilled, at most once a minute
+func (limiter *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(limiter.swept) < time.Minute {
+		return
+	}
+	for key, bucket := range limiter.buckets {
+		if now.After(bucket.full) {
+			delete(limiter.buckets, key)
+		}
+	}
+	limiter.swept = now
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// identifies the client by user, or by ip address if unauthenticated
+// unverified headers such as api keys could be changed for a fresh bucket
+func defaultRateLimitKey(c schema.Context) string {
+	user := c.GetUser()
+	if user != nil {
+		identified, ok := user.(interface {
+			GetID() string
+		})
+		if ok {
+			return "user:" + identified.GetID()
+		}
+		return fmt.Sprintf("user:%v", user)
+	}
+	return "ip:" + clientAddress(c.GetRequest())
+}
+
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return host
+}
+
+// counts the request against its ip address before any credentials are checked,
+// so they cannot be guessed without limit
+// returns false if the limit is exceeded and a response has been written
+func authenticationRateLimit(c schema.Context, w http.ResponseWriter) bool {
+	server := c.GetServer()
+	limit := server.GetAuthenticationRateLimit()
+	if !limit.Enabled() || server.GetRateLimiter() == nil {
+		return true
+	}
+	return takeRateLimit(c, w, "auth|ip:"+clientAddress(c.GetRequest()), limit)
+}
+
+// counts the request against the client's limit and sets the rate limit headers
+// returns false if the limit is exceeded and a response has been written
+func rateLimit(c schema.Context, w http.ResponseWriter, m *schema.Model, method string) bool {
+	server := c.GetServer()
+	limitKey, limit := "", server.GetDefaultRateLimit()
+	if m != nil {
+		limitKey, limit = m.RateLimitFor(method, limit)
+	}
+	if !limit.Enabled() || server.GetRateLimiter() == nil {
+		return true
+	}
+
+	// models sharing the server limit share a bucket
+	bucketKey := server.GetRateLimitKey(c)
+	if len(limitKey) > 0 {
+		bucketKey += "|" + m.Type + "|" + limitKey
+	}
+	return takeRateLimit(c, w, bucketKey, limit)
+}
+
+func takeRateLimit(c schema.Context, w http.ResponseWriter, bucketKey string, limit schema.RateLimit) bool {
+	result := c.GetServer().GetRateLimiter().Take(bucketKey, limit)
+
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit.Requests))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.Reset.Seconds()))))
+	if result.Allowed {
+		return true
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+	limited := utils.TooManyRequests()
+	writeExceptions(c, w, limited.Status, []Exception{
+		Exception{
+			Status: strconv.Itoa(limited.Status),
+			Title:  "Too Many Requests",
+			Detail: limited.Error(),
+		},
+	})
+	return false
+}
@@ -0,0 +1,144 @@
+package servers
+
+import (
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+// the alias of the owning table when listing the instances related to it
+const RELATED_OWNER_ALIAS = "related_owner"
+
+func RelatedHandler(
+	s schema.Server,
+	m *schema.Model,
+	relationship schema.Relationship,
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	rc := NewRequestContext(s, w, r)
+	w = rc.ResponseWriter
+	defer catchExceptions(rc, w)()
+	methods := []string{}
+	if schema.Readable(relationship.GetAccess()) {
+		methods = append(methods, "GET")
+	}
+	_, allowed := allowMethods(rc, w, methods...)
+	if !allowed {
+		return
+	}
+	err := rc.Authenticate()
+	if err != nil {
+		return
+	}
+	if !rateLimit(rc, w, m, "GET") {
+		return
+	}
+	defer snapshotReads(rc, "GET")()
+
+	// parse query strings
+	queryStrings := r.URL.Query()
+
+	// extract id
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	relatedGET(w, r, rc, m, relationship, id, queryStrings)
+
+	rc.LogStats()
+}
+
+func relatedGET(
+	w http.ResponseWriter,
+	r *http.Request,
+	c schema.Context,
+	m *schema.Model,
+	relationship schema.Relationship,
+	id string,
+	queryStrings map[string][]string,
+) {
+	// get parent object with every related item
+	noInclude := schema.Include{
+		Children: map[string]*schema.Include{},
+	}
+	instances, _, err := c.GetObjectsByIDs(m, []string{id}, &noInclude)
+	if err != nil {
+		panic(err)
+	}
+	if len(instances) == 0 {
+		NotFound(c, w, m.Type, id)
+		return
+	}
+	hasAccess := c.CanAccessAllInstances(instances)
+	if !hasAccess {
+		Forbidden(c, w, "Forbidden", "You do not have access to this object.")
+		return
+	}
+	if !m.Manager.CanReadField(c.GetUser(), instances[0], relationship.GetKey()) {
+		Forbidden(c, w, "Forbidden", "You cannot view this relationship.")
+		return
+	}
+	if isToMany(relationship) {
+		relatedModel := c.GetServer().GetModel(relationship.GetType())
+		if relatedModel == nil {
+			panic(fmt.Sprintf("Could not find model for model: %s", relationship.GetType()))
+		}
+		include, err := parseInclude(c, relatedModel, queryStrings)
+		if err != nil {
+			BadParameter(c, w, "Bad Included Relations Parameter", INCLUDE_ARG, err.Error())
+			return
+		}
+		err = parseFields(c, include, queryStrings)
+		if err != nil {
+			BadRequest(c, w, "Bad Sparse Fieldset Parameter", err.Error())
+			return
+		}
+
+		// limit the listing to the related items, through the relation's own tables
+		through, ok := relationship.(schema.FilteringRelationship)
+		if !ok {
+			panic(fmt.Sprintf("Relation %s cannot be listed", relationship.GetKey()))
+		}
+		scopeQueries := []string{fmt.Sprintf(
+			"exists (select 1 from %s as %s where %s.%s = $1 and %s)",
+			m.Table,
+			RELATED_OWNER_ALIAS,
+			RELATED_OWNER_ALIAS,
+			m.IDColumn,
+			through.GetFilterCondition(RELATED_OWNER_ALIAS, m.IDColumn, relatedModel.Table, relatedModel.IDColumn),
+		)}
+		scopeArgs := []interface{}{id}
+		scopedListGET(w, r, c, relatedModel, queryStrings, include, scopeQueries, scopeArgs)
+		return
+	}
+
+	// to one relations return the single related item or null
+	value := instances[0].GetValues()[relationship.GetKey()]
+	result, ok := value.(schema.Result)
+	if !ok {
+		panic("Bad result value")
+	}
+	if result.Data == nil {
+		c.WriteToResponse(struct {
+			Data interface{} `json:"data"`
+		}{})
+		return
+	}
+	pointer := schema.AssertInstancePointer(result.Data)
+	relatedModel := c.GetServer().GetModel(pointer.GetType())
+	if relatedModel == nil {
+		panic(fmt.Sprintf("Could not find model for model: %s", pointer.GetType()))
+	}
+	include, err := parseInclude(c, relatedModel, queryStrings)
+	if err != nil {
+		BadParameter(c, w, "Bad Included Relations Parameter", INCLUDE_ARG, err.Error())
+		return
+	}
+	err = parseFields(c, include, queryStrings)
+	if err != nil {
+		BadRequest(c, w, "Bad Sparse Fieldset Parameter", err.Error())
+		return
+	}
+	detailGET(w, r, c, relatedModel, pointer.GetID(), include)
+}
@@ -1,11 +1,12 @@
 package servers
 
 import (
-	"fmt"
+	"encoding/json"
+	"errors"
 	"github.com/bor3ham/reja/schema"
 	"github.com/gorilla/mux"
+	"io/ioutil"
 	"net/http"
-	"strings"
 )
 
 func RelationHandler(
@@ -16,90 +17,181 @@ func RelationHandler(
 	r *http.Request,
 ) {
 	rc := NewRequestContext(s, w, r)
+	w = rc.ResponseWriter
 	defer catchExceptions(rc, w)()
-	err := rc.Authenticate()
-	if err != nil {
+	// changing a relation updates the instance
+	methods := []string{}
+	if schema.Readable(relationship.GetAccess()) {
+		methods = append(methods, "GET")
+	}
+	if m.AllowsMethod("PATCH") && schema.Updatable(relationship.GetAccess()) {
+		methods = append(methods, "PATCH")
+		if isToMany(relationship) {
+			methods = append(methods, "POST", "DELETE")
+		}
+	}
+	method, allowed := allowMethods(rc, w, methods...)
+	if !allowed {
 		return
 	}
-
-	// parse query strings
-	queryStrings := r.URL.Query()
-	// todo: condense into helper method (same as list_get.go)
-	// get pagination stuff
-	minPageSize := 1
-	maxPageSize := rc.GetServer().GetMaximumDirectPageSize()
-	pageSize, err := GetIntParam(
-		queryStrings,
-		"page[size]",
-		"Page Size",
-		rc.GetServer().GetDefaultDirectPageSize(),
-		&minPageSize,
-		&maxPageSize,
-	)
+	err := rc.Authenticate()
 	if err != nil {
-		BadRequest(rc, w, "Bad Page Size Parameter", err.Error())
 		return
 	}
-	minPageOffset := 1
-	pageOffset, err := GetIntParam(
-		queryStrings,
-		"page[offset]",
-		"Page Offset",
-		1,
-		&minPageOffset,
-		nil,
-	)
-	if err != nil {
-		BadRequest(rc, w, "Bad Page Offset Parameter", err.Error())
+	if !rateLimit(rc, w, m, method) {
 		return
 	}
-	offset := (pageOffset - 1) * pageSize
+	defer snapshotReads(rc, method)()
 
 	// parse query strings
-	_ = r.URL.Query()
+	queryStrings := r.URL.Query()
 
 	// extract id
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	// get parent object
-	instances, _, err := rc.GetObjectsByIDs(m, []string{id}, &schema.Include{})
+	// handle request based on method
+	if method == "PATCH" {
+		relationPATCH(w, r, rc, m, relationship, id, queryStrings)
+	} else if method == "POST" {
+		relationPOST(w, r, rc, m, relationship, id, queryStrings)
+	} else if method == "DELETE" {
+		relationDELETE(w, r, rc, m, relationship, id, queryStrings)
+	} else if method == "GET" {
+		relationGET(w, r, rc, m, relationship, id, queryStrings)
+	}
+
+	rc.LogStats()
+}
+
+func isToMany(relationship schema.Relationship) bool {
+	_, isPage := relationship.GetDefaultValue().(schema.Page)
+	return isPage
+}
+
+// reads a relationship document from the request body, requiring top level data
+func readRelationData(r *http.Request, value interface{}) error {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		panic(err)
+	}
+	var document map[string]json.RawMessage
+	err = json.Unmarshal(body, &document)
+	if err != nil {
+		return err
+	}
+	_, exists := document["data"]
+	if !exists {
+		return errors.New("Relationship document must contain top level data.")
+	}
+	return json.Unmarshal(body, value)
+}
+
+// loads the instance owning the relation with all related items, for changing the relation
+// returns nil if the instance could not be used and a response has been written
+func relationInstance(
+	w http.ResponseWriter,
+	c schema.Context,
+	m *schema.Model,
+	relationship schema.Relationship,
+	id string,
+) schema.Instance {
+	noInclude := schema.Include{
+		Children: map[string]*schema.Include{},
+	}
+	instances, _, err := c.GetObjectsByIDsAllRelations(m, []string{id}, &noInclude)
 	if err != nil {
 		panic(err)
 	}
-	// abort if it doesn't exist
 	if len(instances) == 0 {
-		NotFound(rc, w, m.Type, id)
-		return
+		NotFound(c, w, m.Type, id)
+		return nil
+	}
+	hasAccess := c.CanAccessAllInstances(instances)
+	if !hasAccess {
+		Forbidden(c, w, "Forbidden", "You do not have access to this object.")
+		return nil
 	}
+	// changing a relation updates the instance
+	err = authoriseUpdate(c, m, instances[0])
+	if err != nil {
+		writeRequestError(c, w, err)
+		return nil
+	}
+	if !m.Manager.CanWriteField(c.GetUser(), instances[0], relationship.GetKey()) {
+		Forbidden(c, w, "Forbidden", "You cannot change this relationship.")
+		return nil
+	}
+	return instances[0]
+}
 
-	extraColumns, _ := relationship.GetSelectExtra()
-	var extraVariables [][]interface{}
-	if len(extraColumns) > 0 {
-		rows, err := rc.Query(fmt.Sprintf(
-			`select %s from %s where %s = $1`,
-			strings.Join(extraColumns, ", "),
-			m.Table,
-			m.IDColumn,
-		), id)
-		if err != nil {
-			panic(err)
+// validates and saves a complete replacement value for a relation
+// returns false if the value was rejected and a response has been written
+func replaceRelation(
+	w http.ResponseWriter,
+	c schema.Context,
+	m *schema.Model,
+	relationship schema.Relationship,
+	instance schema.Instance,
+	value interface{},
+) bool {
+	originalsMap := instance.GetValues()
+	originals := valuesFromMap(originalsMap, m.Attributes, m.Relationships)
+
+	// leave every other value untouched
+	updates := make([]interface{}, len(originals))
+	valueIndex := len(m.Attributes)
+	for _, relation := range m.Relationships {
+		if relation.GetKey() == relationship.GetKey() {
+			break
 		}
-		defer rows.Close()
-		for rows.Next() {
-			_, vars := relationship.GetSelectExtra()
-			rows.Scan(vars...)
-			extraVariables = append(extraVariables, vars)
+		valueIndex += 1
+	}
+
+	var err error
+	updates[valueIndex], err = relationship.ValidateUpdate(c, value, originals[valueIndex])
+	if err != nil {
+		// the relationship is the whole document on its own endpoint
+		exceptions := validationExceptions("Bad Relationship Value", err, "/data")
+		for index, _ := range exceptions {
+			exceptions[index].Source = &ExceptionSource{
+				Pointer: "/data",
+			}
 		}
+		writeRequestError(c, w, validationRequestError(exceptions))
+		return false
 	}
-	values, _ := relationship.GetValues(rc, m, []string{id}, extraVariables, offset, pageSize)
-	defaultValue := relationship.GetDefaultValue()
-	var responseBlob interface{}
-	responseBlob, exists := values[id]
-	if !exists {
-		responseBlob = defaultValue
+	// nothing to do if unchanged
+	if updates[valueIndex] == nil {
+		return true
 	}
+	return saveUpdates(w, c, m, instance.GetID(), originalsMap, originals, updates, false)
+}
 
-	rc.WriteToResponse(responseBlob)
-	rc.LogStats()
+// converts page data into the raw pointer form sent by clients
+func rawPointers(data []interface{}) ([]map[string]interface{}, error) {
+	pointers := []map[string]interface{}{}
+	for _, item := range data {
+		switch pointer := item.(type) {
+		case schema.InstancePointer:
+			pointers = append(pointers, map[string]interface{}{
+				"id":   pointer.GetID(),
+				"type": pointer.GetType(),
+			})
+		case map[string]interface{}:
+			_, validID := pointer["id"].(string)
+			_, validType := pointer["type"].(string)
+			if !validID || !validType {
+				return nil, errors.New("Invalid pointer in relationship data.")
+			}
+			pointers = append(pointers, pointer)
+		default:
+			return nil, errors.New("Invalid pointer in relationship data.")
+		}
+	}
+	return pointers, nil
+}
+
+func rawPointerKey(pointer map[string]interface{}) string {
+	return pointer["type"].(string) + ":" + pointer["id"].(string)
 }
@@ -0,0 +1,62 @@
+package servers
+
+import (
+	"github.com/bor3ham/reja/schema"
+	"net/http"
+)
+
+func relationDELETE(
+	w http.ResponseWriter,
+	r *http.Request,
+	c schema.Context,
+	m *schema.Model,
+	relationship schema.Relationship,
+	id string,
+	queryStrings map[string][]string,
+) {
+	instance := relationInstance(w, c, m, relationship, id)
+	if instance == nil {
+		return
+	}
+
+	// parse the items to remove
+	var page schema.Page
+	err := readRelationData(r, &page)
+	if err != nil {
+		BadRequest(c, w, "Unable to Parse JSON", err.Error())
+		return
+	}
+	removing, err := rawPointers(page.Data)
+	if err != nil {
+		BadRequest(c, w, "Bad Relationship Value", err.Error())
+		return
+	}
+	removed := map[string]bool{}
+	for _, pointer := range removing {
+		removed[rawPointerKey(pointer)] = true
+	}
+
+	// reduce the existing set, ignoring items not present
+	original := instance.GetValues()[relationship.GetKey()]
+	existing, err := rawPointers(schema.AssertPage(original).Data)
+	if err != nil {
+		panic(err)
+	}
+	remaining := []interface{}{}
+	for _, pointer := range existing {
+		if !removed[rawPointerKey(pointer)] {
+			remaining = append(remaining, pointer)
+		}
+	}
+
+	saved := replaceRelation(w, c, m, relationship, instance, schema.Page{
+		Provided: true,
+		Data:     remaining,
+	})
+	if !saved {
+		return
+	}
+
+	// return updated relation as though it were a GET
+	relationGET(w, r, c, m, relationship, id, queryStrings)
+}
@@ -0,0 +1,98 @@
+package servers
+
+import (
+	"fmt"
+	"github.com/bor3ham/reja/schema"
+	"github.com/bor3ham/reja/utils"
+	"net/http"
+	"strings"
+)
+
+func relationGET(
+	w http.ResponseWriter,
+	r *http.Request,
+	c schema.Context,
+	m *schema.Model,
+	relationship schema.Relationship,
+	id string,
+	queryStrings map[string][]string,
+) {
+	// todo: condense into helper method (same as list_get.go)
+	// get pagination stuff
+	minPageSize := 1
+	maxPageSize := c.GetServer().GetMaximumDirectPageSize()
+	pageSize, err := GetIntParam(
+		queryStrings,
+		"page[size]",
+		"Page Size",
+		c.GetServer().GetDefaultDirectPageSize(),
+		&minPageSize,
+		&maxPageSize,
+	)
+	if err != nil {
+		BadParameter(c, w, "Bad Page Size Parameter", utils.PAGE_SIZE, err.Error())
+		return
+	}
+	minPageOffset := 1
+	pageOffset, err := GetIntParam(
+		queryStrings,
+		"page[offset]",
+		"Page Offset",
+		1,
+		&minPageOffset,
+		nil,
+	)
+	if err != nil {
+		BadParameter(c, w, "Bad Page Offset Parameter", utils.PAGE_OFFSET, err.Error())
+		return
+	}
+	offset := (pageOffset - 1) * pageSize
+
+	// get parent object
+	instances, _, err := c.GetObjectsByIDs(m, []string{id}, &schema.Include{})
+	if err != nil {
+		panic(err)
+	}
+	// abort if it doesn't exist
+	if len(instances) == 0 {
+		NotFound(c, w, m.Type, id)
+		return
+	}
+	if !m.Manager.CanReadField(c.GetUser(), instances[0], relationship.GetKey()) {
+		Forbidden(c, w, "Forbidden", "You cannot view this relationship.")
+		return
+	}
+
+	extraColumns, _ := relationship.GetSelectExtra()
+	var extraVariables [][]interface{}
+	if len(extraColumns) > 0 {
+		rows, err := c.Query(fmt.Sprintf(
+			`select %s from %s where %s = $1`,
+			strings.Join(extraColumns, ", "),
+			m.Table,
+			m.IDColumn,
+		), id)
+		if err != nil {
+			panic(err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			_, vars := relationship.GetSelectExtra()
+			rows.Scan(vars...)
+			extraVariables = append(extraVariables, vars)
+		}
+		err = rows.Err()
+		if err != nil {
+			panic(err)
+		}
+	}
+	values, _ := relationship.GetValues(c, m, []string{id}, extraVariables, offset, pageSize)
+	defaultValue := relationship.GetDefaultValue()
+	var responseBlob interface{}
+	responseBlob, exists := values[id]
+	if !exists {
+		responseBlob = defaultValue
+	}
+
+	c.WriteToResponse(responseBlob)
+}
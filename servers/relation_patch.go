@@ -0,0 +1,46 @@
+package servers
+
+import (
+	"github.com/bor3ham/reja/schema"
+	"net/http"
+)
+
+func relationPATCH(
+	w http.ResponseWriter,
+	r *http.Request,
+	c schema.Context,
+	m *schema.Model,
+	relationship schema.Relationship,
+	id string,
+	queryStrings map[string][]string,
+) {
+	instance := relationInstance(w, c, m, relationship, id)
+	if instance == nil {
+		return
+	}
+
+	// parse the replacement value
+	var value interface{}
+	var err error
+	if isToMany(relationship) {
+		var page schema.Page
+		err = readRelationData(r, &page)
+		value = page
+	} else {
+		var result schema.Result
+		err = readRelationData(r, &result)
+		value = result
+	}
+	if err != nil {
+		BadRequest(c, w, "Unable to Parse JSON", err.Error())
+		return
+	}
+
+	saved := replaceRelation(w, c, m, relationship, instance, value)
+	if !saved {
+		return
+	}
+
+	// return updated relation as though it were a GET
+	relationGET(w, r, c, m, relationship, id, queryStrings)
+}
@@ -0,0 +1,62 @@
+package servers
+
+import (
+	"github.com/bor3ham/reja/schema"
+	"net/http"
+)
+
+func relationPOST(
+	w http.ResponseWriter,
+	r *http.Request,
+	c schema.Context,
+	m *schema.Model,
+	relationship schema.Relationship,
+	id string,
+	queryStrings map[string][]string,
+) {
+	instance := relationInstance(w, c, m, relationship, id)
+	if instance == nil {
+		return
+	}
+
+	// parse the items to add
+	var page schema.Page
+	err := readRelationData(r, &page)
+	if err != nil {
+		BadRequest(c, w, "Unable to Parse JSON", err.Error())
+		return
+	}
+	adding, err := rawPointers(page.Data)
+	if err != nil {
+		BadRequest(c, w, "Bad Relationship Value", err.Error())
+		return
+	}
+
+	// extend the existing set, ignoring items already present
+	original := instance.GetValues()[relationship.GetKey()]
+	existing, err := rawPointers(schema.AssertPage(original).Data)
+	if err != nil {
+		panic(err)
+	}
+	known := map[string]bool{}
+	combined := []interface{}{}
+	for _, pointer := range append(existing, adding...) {
+		key := rawPointerKey(pointer)
+		if known[key] {
+			continue
+		}
+		known[key] = true
+		combined = append(combined, pointer)
+	}
+
+	saved := replaceRelation(w, c, m, relationship, instance, schema.Page{
+		Provided: true,
+		Data:     combined,
+	})
+	if !saved {
+		return
+	}
+
+	// return updated relation as though it were a GET
+	relationGET(w, r, c, m, relationship, id, queryStrings)
+}
@@ -1,16 +1,18 @@
 package servers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"github.com/bor3ham/reja/schema"
 	"github.com/bor3ham/reja/utils"
-	"github.com/gorilla/context"
+	gcontext "github.com/gorilla/context"
 	"github.com/mailru/easyjson"
 	"github.com/mailru/easyjson/jwriter"
 	"log"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -27,6 +29,20 @@ type RequestContext struct {
 	gorillaMutex   sync.Mutex
 	began          time.Time
 
+	// every statement of the request runs under this
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	workers *workerPool
+
+	// shared instance cache lookups made by this request
+	sharedHits   int64
+	sharedMisses int64
+
+	// when bound, all queries run one at a time through a single transaction
+	boundTx    *sql.Tx
+	boundMutex sync.Mutex
+
 	InstanceCache struct {
 		sync.Mutex
 		Instances map[string]map[string]CachedInstance
@@ -34,11 +50,27 @@ type RequestContext struct {
 }
 
 func NewRequestContext(s schema.Server, w http.ResponseWriter, r *http.Request) *RequestContext {
+	if r.Method == http.MethodHead {
+		w = headResponseWriter{w}
+	}
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		w = &conditionalResponseWriter{
+			ResponseWriter: w,
+			request:        r,
+		}
+	}
 	rc := RequestContext{
 		Server:         s,
 		Request:        r,
 		ResponseWriter: w,
 		began:          time.Now(),
+		workers:        newWorkerPool(s.GetMaximumWorkers()),
+	}
+	timeout := s.GetStatementTimeout()
+	if timeout > 0 {
+		rc.ctx, rc.cancel = context.WithTimeout(r.Context(), timeout)
+	} else {
+		rc.ctx, rc.cancel = context.WithCancel(r.Context())
 	}
 	rc.InitCache()
 	return &rc
@@ -50,7 +82,22 @@ func (rc *RequestContext) GetRequest() *http.Request {
 func (rc *RequestContext) GetServer() schema.Server {
 	return rc.Server
 }
+func (rc *RequestContext) GetContext() context.Context {
+	return rc.ctx
+}
+
+// stops any statements still running and sends the response once the request is handled
+func (rc *RequestContext) Finish() {
+	rc.cancel()
+	conditional, ok := rc.ResponseWriter.(*conditionalResponseWriter)
+	if ok {
+		conditional.flush()
+	}
+}
 func (rc *RequestContext) Authenticate() error {
+	if !authenticationRateLimit(rc, rc.ResponseWriter) {
+		return utils.TooManyRequests()
+	}
 	user, err := rc.Server.Authenticate(rc.ResponseWriter, rc.Request, rc)
 	if err != nil {
 		authError, ok := err.(utils.AuthError)
@@ -111,11 +158,11 @@ func (rc *RequestContext) IncrementQueryCount() {
 	rc.gorillaMutex.Lock()
 	queries := rc.GetQueryCount()
 	queries += 1
-	context.Set(rc.Request, "queries", queries)
+	gcontext.Set(rc.Request, "queries", queries)
 	rc.gorillaMutex.Unlock()
 }
 func (rc *RequestContext) GetQueryCount() int {
-	current := context.Get(rc.Request, "queries")
+	current := gcontext.Get(rc.Request, "queries")
 	if current != nil {
 		currentInt, ok := current.(int)
 		if !ok {
@@ -135,26 +182,53 @@ func (rc *RequestContext) LogStats() {
 	log.Println("\t", rc.Request.Method, rc.Request.URL.String())
 	log.Println("Database queries:", rc.GetQueryCount())
 	log.Println("Request duration:", time.Since(rc.began))
+	peak, spawned, inline := rc.workers.stats()
+	log.Println("Concurrent workers:", peak, "peak,", spawned, "spawned,", inline, "inline")
+	cache := rc.Server.GetInstanceCache()
+	if cache != nil {
+		stats := cache.Stats()
+		log.Println(
+			"Instance cache:", atomic.LoadInt64(&rc.sharedHits), "hits,", atomic.LoadInt64(&rc.sharedMisses), "misses,",
+			"totals", stats.Hits, "hits,", stats.Misses, "misses,", stats.Entries, "entries",
+		)
+	}
 	log.Println()
 }
 
 func (rc *RequestContext) QueryRow(query string, args ...interface{}) *sql.Row {
 	rc.LogQuery(query)
 	rc.IncrementQueryCount()
-	return rc.Server.GetDatabase().QueryRow(query, args...)
+	if rc.boundTx != nil {
+		return rc.boundTx.QueryRowContext(rc.ctx, query, args...)
+	}
+	return rc.Server.GetDatabase().QueryRowContext(rc.ctx, query, args...)
 }
 func (rc *RequestContext) Query(query string, args ...interface{}) (*sql.Rows, error) {
 	rc.LogQuery(query)
 	rc.IncrementQueryCount()
-	return rc.Server.GetDatabase().Query(query, args...)
+	if rc.boundTx != nil {
+		return rc.boundTx.QueryContext(rc.ctx, query, args...)
+	}
+	return rc.Server.GetDatabase().QueryContext(rc.ctx, query, args...)
 }
 func (rc *RequestContext) Exec(query string, args ...interface{}) (sql.Result, error) {
 	rc.LogQuery(query)
 	rc.IncrementQueryCount()
-	return rc.Server.GetDatabase().Exec(query, args...)
+	if rc.boundTx != nil {
+		return rc.boundTx.ExecContext(rc.ctx, query, args...)
+	}
+	return rc.Server.GetDatabase().ExecContext(rc.ctx, query, args...)
 }
 func (rc *RequestContext) Begin() (schema.Transaction, error) {
-	tx, err := rc.Server.GetDatabase().Begin()
+	// nested transactions are left to the bound one to commit or roll back
+	if rc.boundTx != nil {
+		return &ContextTransaction{
+			rc:     rc,
+			tx:     rc.boundTx,
+			nested: true,
+		}, nil
+	}
+	tx, err := rc.Server.GetDatabase().BeginTx(rc.ctx, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -164,6 +238,49 @@ func (rc *RequestContext) Begin() (schema.Transaction, error) {
 	}, nil
 }
 
+// starts a transaction that every following query of the request runs within
+// until it is committed or rolled back
+func (rc *RequestContext) BeginBound() (schema.Transaction, error) {
+	return rc.beginBound(nil)
+}
+
+// binds the context to a read only transaction, so every read sees the same snapshot
+func (rc *RequestContext) BeginSnapshot() (schema.Transaction, error) {
+	return rc.beginBound(&sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+}
+
+func (rc *RequestContext) beginBound(options *sql.TxOptions) (schema.Transaction, error) {
+	if rc.boundTx != nil {
+		panic("Request context is already bound to a transaction")
+	}
+	tx, err := rc.Server.GetDatabase().BeginTx(rc.ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	rc.boundTx = tx
+	return &ContextTransaction{
+		rc:    rc,
+		tx:    tx,
+		bound: true,
+	}, nil
+}
+
+// a transaction connection can only run one query at a time, so concurrent
+// lookups must hold this while querying and reading rows
+func (rc *RequestContext) lockQueries() {
+	if rc.boundTx != nil {
+		rc.boundMutex.Lock()
+	}
+}
+func (rc *RequestContext) unlockQueries() {
+	if rc.boundTx != nil {
+		rc.boundMutex.Unlock()
+	}
+}
+
 func (rc *RequestContext) InitCache() {
 	rc.InstanceCache.Lock()
 	rc.InstanceCache.Instances = map[string]map[string]CachedInstance{}
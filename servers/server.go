@@ -5,6 +5,8 @@ import (
 	"github.com/bor3ham/reja/schema"
 	"github.com/gorilla/mux"
 	"net/http"
+	"sync"
+	"time"
 )
 
 type Server struct {
@@ -14,6 +16,20 @@ type Server struct {
 	defaultDirectPageSize int
 	maximumDirectPageSize int
 	indirectPageSize      int
+	defaultCountPolicy    string
+	statementTimeout      time.Duration
+	maximumWorkers        int
+
+	rateLimiter             schema.RateLimiter
+	defaultRateLimit        schema.RateLimit
+	authenticationRateLimit schema.RateLimit
+	rateLimitKey            func(schema.Context) string
+
+	instanceCache   schema.InstanceCache
+	defaultCacheTTL time.Duration
+	// counts invalidations per model, so reads from before one are not stored after it
+	cacheGenerations map[string]uint64
+	generationMutex  sync.Mutex
 
 	models map[string]schema.Model
 	routes map[string]string
@@ -21,6 +37,7 @@ type Server struct {
 	logSQL     bool
 	whitespace bool
 	easyJSON   bool
+	snapshots  bool
 }
 
 func New(db schema.Database, auth schema.Authenticator) *Server {
@@ -31,6 +48,14 @@ func New(db schema.Database, auth schema.Authenticator) *Server {
 		defaultDirectPageSize: 50,
 		maximumDirectPageSize: 100,
 		indirectPageSize:      10,
+		defaultCountPolicy:    schema.COUNT_EXACT,
+		maximumWorkers:        8,
+
+		rateLimiter:  NewMemoryRateLimiter(),
+		rateLimitKey: defaultRateLimitKey,
+
+		defaultCacheTTL:  5 * time.Minute,
+		cacheGenerations: map[string]uint64{},
 
 		models: map[string]schema.Model{},
 		routes: map[string]string{},
@@ -38,6 +63,7 @@ func New(db schema.Database, auth schema.Authenticator) *Server {
 		logSQL:     false,
 		whitespace: true,
 		easyJSON:   false,
+		snapshots:  false,
 	}
 }
 
@@ -51,6 +77,18 @@ func (s *Server) UseEasyJSON() bool {
 	return s.easyJSON
 }
 
+// runs each GET in a single read only transaction, so included resources are consistent
+// with the primary data at the cost of running its lookups one at a time
+func (s *Server) EnableSnapshotReads() {
+	s.snapshots = true
+}
+func (s *Server) DisableSnapshotReads() {
+	s.snapshots = false
+}
+func (s *Server) UseSnapshotReads() bool {
+	return s.snapshots
+}
+
 func (s *Server) EnableWhitespace() {
 	s.whitespace = true
 }
@@ -96,11 +134,149 @@ func (s *Server) SetIndirectPageSize(size int) {
 	s.indirectPageSize = size
 }
 
+func (s *Server) GetDefaultCountPolicy() string {
+	return s.defaultCountPolicy
+}
+func (s *Server) SetDefaultCountPolicy(policy string) {
+	if !schema.ValidCountPolicy(policy) {
+		panic(fmt.Sprintf("Unknown count policy %s!", policy))
+	}
+	s.defaultCountPolicy = policy
+}
+
+// the time allowed for all the statements of a request, unlimited if zero
+func (s *Server) GetStatementTimeout() time.Duration {
+	return s.statementTimeout
+}
+func (s *Server) SetStatementTimeout(timeout time.Duration) {
+	s.statementTimeout = timeout
+}
+
+// the goroutines each request may run at once to look up relations and includes
+// unlimited if zero
+func (s *Server) GetMaximumWorkers() int {
+	return s.maximumWorkers
+}
+func (s *Server) SetMaximumWorkers(workers int) {
+	s.maximumWorkers = workers
+}
+
+func (s *Server) GetRateLimiter() schema.RateLimiter {
+	return s.rateLimiter
+}
+func (s *Server) SetRateLimiter(limiter schema.RateLimiter) {
+	s.rateLimiter = limiter
+}
+
+// applies to every model without its own limit, disabled by default
+func (s *Server) GetDefaultRateLimit() schema.RateLimit {
+	return s.defaultRateLimit
+}
+func (s *Server) SetDefaultRateLimit(limit schema.RateLimit) {
+	s.defaultRateLimit = limit
+}
+
+// applies to each ip address before authentication, disabled by default
+// as clients behind a proxy would share one address
+func (s *Server) GetAuthenticationRateLimit() schema.RateLimit {
+	return s.authenticationRateLimit
+}
+func (s *Server) SetAuthenticationRateLimit(limit schema.RateLimit) {
+	s.authenticationRateLimit = limit
+}
+
+func (s *Server) GetRateLimitKey(c schema.Context) string {
+	return s.rateLimitKey(c)
+}
+func (s *Server) SetRateLimitKey(key func(schema.Context) string) {
+	s.rateLimitKey = key
+}
+
+// shares instances between requests, disabled if nil
+func (s *Server) GetInstanceCache() schema.InstanceCache {
+	return s.instanceCache
+}
+func (s *Server) SetInstanceCache(cache schema.InstanceCache) {
+	s.instanceCache = cache
+}
+
+func (s *Server) GetDefaultCacheTTL() time.Duration {
+	return s.defaultCacheTTL
+}
+func (s *Server) SetDefaultCacheTTL(ttl time.Duration) {
+	s.defaultCacheTTL = ttl
+}
+
+// forgets cached instances of a model and of every model whose relations may include it
+func (s *Server) InvalidateCache(modelType string) {
+	if s.instanceCache == nil {
+		return
+	}
+	model, exists := s.models[modelType]
+	if !exists {
+		return
+	}
+	invalidate := map[string]bool{
+		modelType: true,
+	}
+	for _, relationship := range model.Relationships {
+		relatedType := relationship.GetType()
+		// generic relations can point at anything
+		if len(relatedType) == 0 {
+			for otherType, _ := range s.models {
+				invalidate[otherType] = true
+			}
+		}
+		invalidate[relatedType] = true
+	}
+	for otherType, other := range s.models {
+		for _, relationship := range other.Relationships {
+			relatedType := relationship.GetType()
+			if relatedType == modelType || len(relatedType) == 0 {
+				invalidate[otherType] = true
+			}
+		}
+	}
+	s.generationMutex.Lock()
+	for invalidType, _ := range invalidate {
+		s.cacheGenerations[invalidType] += 1
+	}
+	s.generationMutex.Unlock()
+	for invalidType, _ := range invalidate {
+		if len(invalidType) > 0 {
+			s.instanceCache.Invalidate(invalidType)
+		}
+	}
+}
+func (s *Server) GetCacheGeneration(modelType string) uint64 {
+	s.generationMutex.Lock()
+	defer s.generationMutex.Unlock()
+	return s.cacheGenerations[modelType]
+}
+
 func (s *Server) RegisterModel(model *schema.Model) {
 	_, exists := s.models[model.Type]
 	if exists {
 		panic(fmt.Sprintf("Model %s already registered!", model.Type))
 	}
+	if len(model.CountPolicy) > 0 && !schema.ValidCountPolicy(model.CountPolicy) {
+		panic(fmt.Sprintf("Unknown count policy %s on model %s!", model.CountPolicy, model.Type))
+	}
+	for _, attribute := range model.Attributes {
+		if !schema.ValidAccess(attribute.GetAccess()) {
+			panic(fmt.Sprintf("Unknown access %s on attribute %s!", attribute.GetAccess(), attribute.GetKey()))
+		}
+	}
+	for _, relationship := range model.Relationships {
+		if !schema.ValidAccess(relationship.GetAccess()) {
+			panic(fmt.Sprintf("Unknown access %s on relationship %s!", relationship.GetAccess(), relationship.GetKey()))
+		}
+	}
+	for method, limit := range model.RateLimits {
+		if !limit.Enabled() {
+			panic(fmt.Sprintf("Rate limit for %s on model %s must allow requests over time!", method, model.Type))
+		}
+	}
 	s.models[model.Type] = *model
 }
 func (s *Server) GetModel(modelType string) *schema.Model {
@@ -169,5 +345,27 @@ func (s *Server) Handle(router *mux.Router, modelType string, path string) {
 				RelationHandler(s, &model, relation, w, r)
 			},
 		)
+		router.HandleFunc(
+			path+`/{id:[0-9a-zA-Z\-\_]+}/`+relation.GetKey(),
+			func(w http.ResponseWriter, r *http.Request) {
+				RelatedHandler(s, &model, relation, w, r)
+			},
+		)
+		router.HandleFunc(
+			path+`/{id:[0-9a-zA-Z\-\_]+}/`+relation.GetKey()+"/",
+			func(w http.ResponseWriter, r *http.Request) {
+				RelatedHandler(s, &model, relation, w, r)
+			},
+		)
 	}
 }
+
+// serves atomic operations across all registered models at the path
+func (s *Server) HandleOperations(router *mux.Router, path string) {
+	router.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		OperationsHandler(s, w, r)
+	})
+	router.HandleFunc(path+"/", func(w http.ResponseWriter, r *http.Request) {
+		OperationsHandler(s, w, r)
+	})
+}
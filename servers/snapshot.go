@@ -0,0 +1,17 @@
+package servers
+
+// runs the reads of a GET request in one snapshot when enabled on the server
+// returns a function that ends the snapshot once the response is written
+func snapshotReads(rc *RequestContext, method string) func() {
+	if method != "GET" || !rc.GetServer().UseSnapshotReads() {
+		return func() {}
+	}
+	tx, err := rc.BeginSnapshot()
+	if err != nil {
+		panic(err)
+	}
+	return func() {
+		// nothing was written, so there is nothing to commit
+		tx.Rollback()
+	}
+}
@@ -7,26 +7,43 @@ import (
 type ContextTransaction struct {
 	tx *sql.Tx
 	rc *RequestContext
+
+	// nested transactions are committed by whoever bound the context
+	nested bool
+	// bound transactions release the context once finished
+	bound bool
 }
 
 func (t *ContextTransaction) QueryRow(query string, args ...interface{}) *sql.Row {
 	t.rc.LogQuery(query)
 	t.rc.IncrementQueryCount()
-	return t.tx.QueryRow(query, args...)
+	return t.tx.QueryRowContext(t.rc.ctx, query, args...)
 }
 func (t *ContextTransaction) Query(query string, args ...interface{}) (*sql.Rows, error) {
 	t.rc.LogQuery(query)
 	t.rc.IncrementQueryCount()
-	return t.tx.Query(query, args...)
+	return t.tx.QueryContext(t.rc.ctx, query, args...)
 }
 func (t *ContextTransaction) Exec(query string, args ...interface{}) (sql.Result, error) {
 	t.rc.LogQuery(query)
 	t.rc.IncrementQueryCount()
-	return t.tx.Exec(query, args...)
+	return t.tx.ExecContext(t.rc.ctx, query, args...)
 }
 func (t *ContextTransaction) Commit() error {
+	if t.nested {
+		return nil
+	}
+	if t.bound {
+		t.rc.boundTx = nil
+	}
 	return t.tx.Commit()
 }
 func (t *ContextTransaction) Rollback() error {
+	if t.nested {
+		return nil
+	}
+	if t.bound {
+		t.rc.boundTx = nil
+	}
 	return t.tx.Rollback()
 }
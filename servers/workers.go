@@ -0,0 +1,71 @@
+package servers
+
+import (
+	"sync"
+)
+
+// tracks the goroutines a request uses to look up relations and includes
+type workerPool struct {
+	sync.Mutex
+	// holds a token for each running worker, nil if unlimited
+	slots   chan struct{}
+	running int
+	peak    int
+	spawned int
+	inline  int
+}
+
+func newWorkerPool(budget int) *workerPool {
+	pool := workerPool{}
+	if budget > 0 {
+		pool.slots = make(chan struct{}, budget)
+	}
+	return &pool
+}
+
+// runs work in a new goroutine if the budget allows, otherwise in the calling one
+// work never waits for a slot, so nested lookups cannot deadlock
+func (pool *workerPool) run(wg *sync.WaitGroup, work func()) {
+	wg.Add(1)
+	if pool.slots != nil {
+		select {
+		case pool.slots <- struct{}{}:
+		default:
+			pool.Lock()
+			pool.inline += 1
+			pool.Unlock()
+			defer wg.Done()
+			work()
+			return
+		}
+	}
+
+	pool.Lock()
+	pool.spawned += 1
+	pool.running += 1
+	if pool.running > pool.peak {
+		pool.peak = pool.running
+	}
+	pool.Unlock()
+	go func() {
+		defer wg.Done()
+		defer pool.release()
+		work()
+	}()
+}
+
+func (pool *workerPool) release() {
+	pool.Lock()
+	pool.running -= 1
+	pool.Unlock()
+	if pool.slots != nil {
+		<-pool.slots
+	}
+}
+
+// peak concurrent workers, goroutines started and work run inline
+func (pool *workerPool) stats() (int, int, int) {
+	pool.Lock()
+	defer pool.Unlock()
+	return pool.peak, pool.spawned, pool.inline
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"math"
 	"net/url"
 	"strconv"
@@ -9,6 +12,12 @@ import (
 // lazy encoded
 const PAGE_SIZE = "page[size]"
 const PAGE_OFFSET = "page[offset]"
+const PAGE_AFTER = "page[after]"
+const PAGE_BEFORE = "page[before]"
+const PAGE_COUNT = "page[count]"
+
+// total passed for pagination when the number of items was not counted
+const UNKNOWN_TOTAL = -1
 
 func joinQueries(baseUrl string, queries ...map[string][]string) string {
 	fullUrl := baseUrl
@@ -65,16 +74,18 @@ func GetPaginationLinks(
 	firstLink := joinQueries(baseUrl, firstArgs, extraQueries)
 	links["first"] = &firstLink
 
-	// link to the last page
-	lastArgs := map[string][]string{}
-	if pageSize != defaultPageSize {
-		lastArgs[PAGE_SIZE] = []string{strconv.Itoa(pageSize)}
-	}
-	if lastPage != 1 {
-		lastArgs[PAGE_OFFSET] = []string{strconv.Itoa(lastPage)}
+	// link to the last page, unless there is no total to find it from
+	if totalItems != UNKNOWN_TOTAL {
+		lastArgs := map[string][]string{}
+		if pageSize != defaultPageSize {
+			lastArgs[PAGE_SIZE] = []string{strconv.Itoa(pageSize)}
+		}
+		if lastPage != 1 {
+			lastArgs[PAGE_OFFSET] = []string{strconv.Itoa(lastPage)}
+		}
+		lastLink := joinQueries(baseUrl, lastArgs, extraQueries)
+		links["last"] = &lastLink
 	}
-	lastLink := joinQueries(baseUrl, lastArgs, extraQueries)
-	links["last"] = &lastLink
 
 	// link to the previous page
 	links["prev"] = nil
@@ -88,9 +99,9 @@ func GetPaginationLinks(
 		links["prev"] = &prevLink
 	}
 
-	// link to the next page
+	// link to the next page, assumed to exist without a total
 	links["next"] = nil
-	if currentPage < lastPage {
+	if totalItems == UNKNOWN_TOTAL || currentPage < lastPage {
 		nextArgs := map[string][]string{}
 		if pageSize != defaultPageSize {
 			nextArgs[PAGE_SIZE] = []string{strconv.Itoa(pageSize)}
@@ -102,3 +113,69 @@ func GetPaginationLinks(
 
 	return links
 }
+
+func EncodeCursor(values []*string) string {
+	blob, err := json.Marshal(values)
+	if err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(blob)
+}
+
+func DecodeCursor(cursor string) ([]*string, error) {
+	blob, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("Invalid cursor.")
+	}
+	var values []*string
+	err = json.Unmarshal(blob, &values)
+	if err != nil {
+		return nil, errors.New("Invalid cursor.")
+	}
+	return values, nil
+}
+
+func GetCursorPaginationLinks(
+	baseUrl string,
+	pageSize int,
+	defaultPageSize int,
+	currentArgs map[string][]string,
+	prevCursor *string,
+	nextCursor *string,
+	extraQueries map[string][]string,
+) map[string]*string {
+	links := map[string]*string{}
+
+	sizeArgs := map[string][]string{}
+	if pageSize != defaultPageSize {
+		sizeArgs[PAGE_SIZE] = []string{strconv.Itoa(pageSize)}
+	}
+
+	// link to this page
+	selfLink := joinQueries(baseUrl, sizeArgs, currentArgs, extraQueries)
+	links["self"] = &selfLink
+
+	// link to the first page
+	firstLink := joinQueries(baseUrl, sizeArgs, extraQueries)
+	links["first"] = &firstLink
+
+	// link to the previous page
+	links["prev"] = nil
+	if prevCursor != nil {
+		prevArgs := map[string][]string{}
+		prevArgs[PAGE_BEFORE] = []string{*prevCursor}
+		prevLink := joinQueries(baseUrl, sizeArgs, prevArgs, extraQueries)
+		links["prev"] = &prevLink
+	}
+
+	// link to the next page
+	links["next"] = nil
+	if nextCursor != nil {
+		nextArgs := map[string][]string{}
+		nextArgs[PAGE_AFTER] = []string{*nextCursor}
+		nextLink := joinQueries(baseUrl, sizeArgs, nextArgs, extraQueries)
+		links["next"] = &nextLink
+	}
+
+	return links
+}
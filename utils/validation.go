@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+)
+
+// machine readable reasons for a value being rejected
+const CODE_INVALID = "invalid"
+const CODE_NULL = "null"
+const CODE_BLANK = "blank"
+const CODE_TOO_SHORT = "too_short"
+const CODE_TOO_LONG = "too_long"
+const CODE_INCORRECT_TYPE = "incorrect_type"
+const CODE_DUPLICATE = "duplicate"
+const CODE_NOT_FOUND = "not_found"
+const CODE_FORBIDDEN = "forbidden"
+const CODE_INVALID_FILTER = "invalid_filter"
+const CODE_READ_ONLY = "read_only"
+const CODE_CREATE_ONLY = "create_only"
+
+const ATTRIBUTES_POINTER = "/data/attributes/"
+const RELATIONSHIPS_POINTER = "/data/relationships/"
+
+type ValidationError struct {
+	text      string
+	Status    int
+	Code      string
+	Pointer   string
+	Parameter string
+}
+
+func (e ValidationError) Error() string {
+	return e.text
+}
+
+func AttributeError(key string, code string, reason string) ValidationError {
+	return ValidationError{
+		text:    reason,
+		Status:  http.StatusBadRequest,
+		Code:    code,
+		Pointer: ATTRIBUTES_POINTER + key,
+	}
+}
+
+func RelationshipError(key string, code string, reason string) ValidationError {
+	return ValidationError{
+		text:    reason,
+		Status:  http.StatusBadRequest,
+		Code:    code,
+		Pointer: RELATIONSHIPS_POINTER + key,
+	}
+}
+
+func ParameterError(parameter string, code string, reason string) ValidationError {
+	return ValidationError{
+		text:      reason,
+		Status:    http.StatusBadRequest,
+		Code:      code,
+		Parameter: parameter,
+	}
+}
+
+// several validation errors reported together
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	reasons := []string{}
+	for _, err := range e {
+		reasons = append(reasons, err.Error())
+	}
+	return strings.Join(reasons, " ")
+}
+
+// converts any error into validation errors, pointing at the given location if it has none
+func AsValidationErrors(err error, pointer string) ValidationErrors {
+	switch typed := err.(type) {
+	case ValidationErrors:
+		return typed
+	case ValidationError:
+		if len(typed.Pointer) == 0 && len(typed.Parameter) == 0 {
+			typed.Pointer = pointer
+		}
+		return ValidationErrors{typed}
+	}
+	return ValidationErrors{
+		ValidationError{
+			text:    err.Error(),
+			Status:  http.StatusBadRequest,
+			Code:    CODE_INVALID,
+			Pointer: pointer,
+		},
+	}
+}